@@ -6,58 +6,158 @@ import (
 
 	"github.com/charmbracelet/huh"
 	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/schedule"
 	"github.com/spf13/cobra"
 )
 
+var (
+	addName        string
+	addSchedule    string
+	addCommands    []string
+	addHealthcheck string
+	addFile        string
+)
+
 var addCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Agrega un nuevo job",
-	Long:  "Agrega un nuevo job usando una interfaz interactiva",
+	Long: `Agrega un nuevo job.
+
+Sin flags muestra una interfaz interactiva. Para uso desde scripts:
+  orgmcron add --name backup --schedule "@daily" --command "rsync -a /a /b" --healthcheck backup
+  orgmcron add -f job.json
+  cat job.json | orgmcron add -f -`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var (
-			jobName         string
-			scheduleType    string
-			cronExpr        string
-			intervalExpr    string
-			commandsStr     string
-			healthcheckName string
-		)
-
-		// Primer formulario: nombre y tipo de schedule
-		form1 := huh.NewForm(
-			huh.NewGroup(
-				huh.NewInput().
-					Title("Nombre del job").
-					Description("Nombre único para identificar este job").
-					Value(&jobName).
-					Validate(func(s string) error {
-						if s == "" {
-							return fmt.Errorf("el nombre no puede estar vacío")
-						}
-						// Verificar que no exista
-						_, err := config.GetJobByName(s)
-						if err == nil {
-							return fmt.Errorf("ya existe un job con este nombre")
-						}
-						return nil
-					}),
-
-				huh.NewSelect[string]().
-					Title("Tipo de schedule").
-					Description("Selecciona el tipo de programación").
-					Options(
-						huh.NewOption("Intervalo (@every)", "interval"),
-						huh.NewOption("Expresión Cron (* * * * *)", "cron"),
-					).
-					Value(&scheduleType),
-			),
-		)
-
-		if err := form1.Run(); err != nil {
-			return fmt.Errorf("error en el formulario: %w", err)
+		var newJob *config.Job
+		var err error
+
+		switch {
+		case addFile != "":
+			newJob, err = readJobFile(addFile)
+			if err != nil {
+				return err
+			}
+			// Los flags explícitos tienen prioridad sobre el archivo
+			if addName != "" {
+				newJob.Name = addName
+			}
+			if addSchedule != "" {
+				newJob.Schedule = addSchedule
+			}
+			if len(addCommands) > 0 {
+				newJob.Commands = config.CommandsFromText(strings.Join(addCommands, "\n"), nil)
+			}
+			if addHealthcheck != "" {
+				newJob.HealthcheckURL = healthcheckURLFromInput(addHealthcheck)
+			}
+		case addName != "" && addSchedule != "" && len(addCommands) > 0:
+			newJob = &config.Job{
+				Name:           addName,
+				Schedule:       addSchedule,
+				Commands:       config.CommandsFromText(strings.Join(addCommands, "\n"), nil),
+				HealthcheckURL: healthcheckURLFromInput(addHealthcheck),
+			}
+		case isInteractive():
+			newJob, err = addJobInteractive()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("faltan flags requeridos (--name, --schedule, --command) y no hay una terminal para el formulario interactivo")
+		}
+
+		if newJob.Name == "" || newJob.Schedule == "" || len(newJob.Commands) == 0 {
+			return fmt.Errorf("el job requiere nombre, schedule y al menos un comando")
+		}
+		if err := validateSchedule(*newJob); err != nil {
+			return err
+		}
+		if err := validateJob(*newJob); err != nil {
+			return err
+		}
+
+		// Guardar job
+		if err := config.AddJob(*newJob); err != nil {
+			return fmt.Errorf("error guardando job: %w", err)
+		}
+
+		fmt.Printf("\n✓ Job '%s' agregado exitosamente\n", newJob.Name)
+		fmt.Printf("  Schedule: %s\n", scheduleSummary(newJob.Schedule))
+		fmt.Printf("  Comandos: %d\n", len(newJob.Commands))
+		if newJob.HealthcheckURL != "" {
+			fmt.Printf("  Healthcheck: %s\n", newJob.HealthcheckURL)
 		}
+		fmt.Println("\nEl daemon aplica los cambios automáticamente. Si se inició con --no-watch, ejecuta:")
+		fmt.Println("  orgmcron reload")
 
-		// Segundo formulario: schedule específico según el tipo
+		return nil
+	},
+}
+
+// addJobInteractive construye un job con los formularios interactivos
+func addJobInteractive() (*config.Job, error) {
+	// Valores iniciales tomados de los flags que sí se proporcionaron
+	var (
+		jobName         = addName
+		scheduleType    string
+		cronExpr        string
+		intervalExpr    string
+		commandsStr     = strings.Join(addCommands, "\n")
+		healthcheckName = addHealthcheck
+	)
+	if strings.HasPrefix(addSchedule, "@") {
+		scheduleType = "interval"
+		intervalExpr = addSchedule
+	} else if addSchedule != "" {
+		scheduleType = "cron"
+		cronExpr = addSchedule
+	}
+
+	// Primer formulario: nombre y tipo de schedule
+	form1 := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Nombre del job").
+				Description("Nombre único para identificar este job").
+				Value(&jobName).
+				Validate(func(s string) error {
+					if s == "" {
+						return fmt.Errorf("el nombre no puede estar vacío")
+					}
+					// Verificar que no exista
+					_, err := config.GetJobByName(s)
+					if err == nil {
+						return fmt.Errorf("ya existe un job con este nombre")
+					}
+					return nil
+				}),
+
+			huh.NewSelect[string]().
+				Title("Tipo de schedule").
+				Description("Selecciona el tipo de programación").
+				Options(
+					huh.NewOption("Intervalo (@every)", "interval"),
+					huh.NewOption("Expresión Cron (* * * * *)", "cron"),
+				).
+				Value(&scheduleType),
+		),
+	)
+
+	if err := form1.Run(); err != nil {
+		return nil, fmt.Errorf("error en el formulario: %w", err)
+	}
+
+	// El schedule se interpreta en la zona horaria que tendrá el job
+	appConfig, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error cargando configuración: %w", err)
+	}
+	timezone := appConfig.TimezoneFor(config.Job{})
+
+	// Segundo formulario: schedule específico según el tipo, hasta que se
+	// confirme su vista previa
+	var scheduleExpr string
+	for {
 		var form2 *huh.Form
 		if scheduleType == "cron" {
 			form2 = huh.NewForm(
@@ -70,7 +170,8 @@ var addCmd = &cobra.Command{
 							if s == "" {
 								return fmt.Errorf("la expresión cron es requerida")
 							}
-							return nil
+							_, err := schedule.ParseIn(s, timezone)
+							return err
 						}),
 				),
 			)
@@ -100,87 +201,69 @@ var addCmd = &cobra.Command{
 		}
 
 		if err := form2.Run(); err != nil {
-			return fmt.Errorf("error en el formulario: %w", err)
-		}
-
-		// Tercer formulario: comandos y healthcheck
-		form3 := huh.NewForm(
-			huh.NewGroup(
-				huh.NewText().
-					Title("Comandos").
-					Description("Un comando por línea. Se ejecutarán en orden.").
-					Value(&commandsStr).
-					Validate(func(s string) error {
-						if s == "" {
-							return fmt.Errorf("debe proporcionar al menos un comando")
-						}
-						return nil
-					}).
-					CharLimit(10000),
-
-				huh.NewInput().
-					Title("Nombre para healthcheck").
-					Description("Nombre del proyecto para el healthcheck (se construirá la URL automáticamente)").
-					Value(&healthcheckName).
-					Placeholder("prueba"),
-			),
-		)
-
-		if err := form3.Run(); err != nil {
-			return fmt.Errorf("error en el formulario: %w", err)
+			return nil, fmt.Errorf("error en el formulario: %w", err)
 		}
 
-		// Determinar schedule final
-		var schedule string
 		if scheduleType == "cron" {
-			schedule = cronExpr
+			scheduleExpr = cronExpr
 		} else {
-			schedule = intervalExpr
+			scheduleExpr = intervalExpr
 		}
-
-		// Parsear comandos
-		commandLines := strings.Split(commandsStr, "\n")
-		commands := []string{}
-		for _, line := range commandLines {
-			line = strings.TrimSpace(line)
-			if line != "" {
-				commands = append(commands, line)
-			}
+		confirmed, err := confirmSchedule(scheduleExpr, timezone)
+		if err != nil {
+			return nil, err
 		}
-
-		// Construir healthcheck URL
-		var healthcheckURL string
-		if healthcheckName != "" {
-			healthcheckURL = fmt.Sprintf("https://hc.or-gm.com/ping/{pingkey}/%s", healthcheckName)
+		if confirmed {
+			break
 		}
+	}
 
-		// Crear job
-		job := config.Job{
-			Name:           jobName,
-			Schedule:       schedule,
-			Commands:       commands,
-			HealthcheckURL: healthcheckURL,
-		}
+	// Tercer formulario: comandos y healthcheck
+	form3 := huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("Comandos").
+				Description("Un comando por línea. Se ejecutarán en orden.").
+				Value(&commandsStr).
+				Validate(func(s string) error {
+					if s == "" {
+						return fmt.Errorf("debe proporcionar al menos un comando")
+					}
+					return nil
+				}).
+				CharLimit(10000),
 
-		// Guardar job
-		if err := config.AddJob(job); err != nil {
-			return fmt.Errorf("error guardando job: %w", err)
-		}
+			huh.NewInput().
+				Title("Nombre para healthcheck").
+				Description("Nombre del proyecto para el healthcheck (se construirá la URL automáticamente)").
+				Value(&healthcheckName).
+				Placeholder("prueba"),
+		),
+	)
 
-		fmt.Printf("\n✓ Job '%s' agregado exitosamente\n", jobName)
-		fmt.Printf("  Schedule: %s\n", schedule)
-		fmt.Printf("  Comandos: %d\n", len(commands))
-		if healthcheckURL != "" {
-			fmt.Printf("  Healthcheck: %s\n", healthcheckURL)
-		}
-		fmt.Println("\nPara aplicar los cambios, ejecuta:")
-		fmt.Println("  orgmcron reload")
+	if err := form3.Run(); err != nil {
+		return nil, fmt.Errorf("error en el formulario: %w", err)
+	}
 
-		return nil
-	},
+	// Parsear comandos
+	commands := config.CommandsFromText(commandsStr, nil)
+
+	// Construir healthcheck URL
+	healthcheckURL := healthcheckURLFromInput(healthcheckName)
+
+	return &config.Job{
+		Name:           jobName,
+		Schedule:       scheduleExpr,
+		Commands:       commands,
+		HealthcheckURL: healthcheckURL,
+	}, nil
 }
 
 func init() {
+	addCmd.Flags().StringVar(&addName, "name", "", "Nombre del job")
+	addCmd.Flags().StringVar(&addSchedule, "schedule", "", "Schedule (@every 1h, @daily o expresión cron)")
+	addCmd.Flags().StringArrayVar(&addCommands, "command", nil, "Comando a ejecutar (repetible, se ejecutan en orden)")
+	addCmd.Flags().StringVar(&addHealthcheck, "healthcheck", "", "Nombre del healthcheck o URL completa")
+	addCmd.Flags().StringVarP(&addFile, "file", "f", "", "Archivo JSON con la definición del job ('-' para stdin)")
 	rootCmd.AddCommand(addCmd)
 }
-
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/diff"
+	"github.com/spf13/cobra"
+)
+
+// diffContext son las líneas de contexto alrededor de cada cambio en config diff
+const diffContext = 3
+
+var configHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Lista los snapshots de la configuración",
+	Long: `Lista los snapshots guardados en backups/. Antes de cada cambio de la
+configuración (add, edit, remove, pause, config ...) se guarda una copia de
+config.json, el archivo de jobs y jobs.d.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshots, err := config.ListSnapshots()
+		if err != nil {
+			return fmt.Errorf("error leyendo snapshots: %w", err)
+		}
+		if len(snapshots) == 0 {
+			fmt.Println("No hay snapshots guardados.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "SNAPSHOT\tFECHA\tANTES DE")
+		fmt.Fprintln(w, "--------\t-----\t--------")
+		for _, s := range snapshots {
+			before := "orgmcron " + s.Command
+			switch s.Kind {
+			case config.SnapshotRestore:
+				before += " (restauración)"
+			case config.SnapshotUndo:
+				before += " (deshacer)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", s.ID, s.Created.Local().Format("2006-01-02 15:04:05"), before)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var configDiffCmd = &cobra.Command{
+	Use:   "diff <snapshot>",
+	Short: "Muestra los cambios desde un snapshot",
+	Long: `Muestra las diferencias entre un snapshot y la configuración actual. El
+snapshot se indica por su ID o por un prefijo único (ver 'orgmcron config history').`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshot, err := config.GetSnapshot(args[0])
+		if err != nil {
+			return err
+		}
+		out, err := snapshotDiff(snapshot)
+		if err != nil {
+			return err
+		}
+		if out == "" {
+			fmt.Printf("Sin cambios desde el snapshot %s\n", snapshot.ID)
+			return nil
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+var configUndoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Deshace el último cambio de la configuración",
+	Long: `Restaura el snapshot más reciente, deshaciendo el último cambio (también
+un 'config restore'). Ejecutarlo de nuevo deshace el cambio anterior. El estado
+deshecho se guarda como snapshot, así se puede recuperar con
+'orgmcron config restore'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshot, err := config.Undo()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Deshecho: orgmcron %s (snapshot %s)\n", snapshot.Command, snapshot.ID)
+		applyToDaemon()
+		return nil
+	},
+}
+
+var configRestoreCmd = &cobra.Command{
+	Use:   "restore <snapshot>",
+	Short: "Restaura la configuración de un snapshot",
+	Long: `Deja config.json, el archivo de jobs y jobs.d como estaban en un snapshot.
+El estado actual se guarda antes como snapshot, así la restauración se puede
+deshacer con 'orgmcron config undo'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshot, err := config.RestoreSnapshot(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Configuración restaurada al snapshot %s\n", snapshot.ID)
+		applyToDaemon()
+		return nil
+	},
+}
+
+// snapshotDiff retorna las diferencias de cada archivo entre el snapshot y la
+// configuración actual; un archivo que falta de un lado se compara vacío
+func snapshotDiff(snapshot *config.Snapshot) (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	current, err := config.ConfigFiles()
+	if err != nil {
+		return "", err
+	}
+
+	names := append([]string{}, snapshot.Files...)
+	for _, rel := range current {
+		if !snapshot.HasFile(rel) {
+			names = append(names, rel)
+		}
+	}
+	sort.Strings(names)
+
+	var out strings.Builder
+	for _, rel := range names {
+		oldName := rel + " (snapshot " + snapshot.ID + ")"
+		var oldData []byte
+		if snapshot.HasFile(rel) {
+			if oldData, err = snapshot.ReadFile(rel); err != nil {
+				return "", fmt.Errorf("error leyendo %s del snapshot: %w", rel, err)
+			}
+		} else {
+			oldName = rel + " (no existía)"
+		}
+
+		newName := rel + " (actual)"
+		newData, err := os.ReadFile(filepath.Join(configDir, filepath.FromSlash(rel)))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return "", fmt.Errorf("error leyendo %s: %w", rel, err)
+			}
+			newName = rel + " (eliminado)"
+		}
+
+		out.WriteString(diff.Unified(string(oldData), string(newData), oldName, newName, diffContext))
+	}
+	return out.String(), nil
+}
+
+func init() {
+	configCmd.AddCommand(configHistoryCmd)
+	configCmd.AddCommand(configDiffCmd)
+	configCmd.AddCommand(configUndoCmd)
+	configCmd.AddCommand(configRestoreCmd)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/osmargm1202/orgmcron/internal/config"
 	"github.com/spf13/cobra"
@@ -35,17 +36,59 @@ var pingkeyCmd = &cobra.Command{
 		}
 
 		// Configurar nueva pingkey
-		appConfig.PingKey = args[0]
-		if err := config.SaveConfig(appConfig); err != nil {
+		err = config.UpdateConfig(func(c *config.AppConfig) error {
+			c.PingKey = args[0]
+			return nil
+		})
+		if err != nil {
 			return fmt.Errorf("error guardando configuración: %w", err)
 		}
 
-		fmt.Printf("PingKey configurado: %s\n", appConfig.PingKey)
+		fmt.Printf("PingKey configurado: %s\n", args[0])
+		return nil
+	},
+}
+
+var convertTo string
+
+var convertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convierte el archivo de jobs a otro formato",
+	Long: `Convierte el archivo de jobs a JSON, YAML o TOML. El archivo original se
+conserva con la extensión .bak (si ya existe uno, no se sobrescribe y no se
+convierte).
+
+Ejemplos:
+  orgmcron config convert --to yaml
+  orgmcron config convert --to toml
+  orgmcron config convert --to json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := strings.ToLower(convertTo)
+		if format == "yml" {
+			format = config.FormatYAML
+		}
+		switch format {
+		case config.FormatJSON, config.FormatYAML, config.FormatTOML:
+		default:
+			return fmt.Errorf("formato no soportado: %s (usa json, yaml o toml)", convertTo)
+		}
+
+		oldPath, newPath, err := config.ConvertJobs(format)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Jobs convertidos: %s\n", newPath)
+		fmt.Printf("  Respaldo del original: %s.bak\n", oldPath)
 		return nil
 	},
 }
 
 func init() {
+	convertCmd.Flags().StringVar(&convertTo, "to", "", "Formato destino: json, yaml o toml")
+	convertCmd.MarkFlagRequired("to")
+	configCmd.AddCommand(convertCmd)
 	configCmd.AddCommand(pingkeyCmd)
 	rootCmd.AddCommand(configCmd)
 }
@@ -6,70 +6,151 @@ import (
 
 	"github.com/charmbracelet/huh"
 	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/schedule"
 	"github.com/spf13/cobra"
 )
 
+var editSets []string
+
 var editCmd = &cobra.Command{
 	Use:   "edit [job_name]",
 	Short: "Edita un job existente",
-	Long:  "Edita un job existente usando una interfaz interactiva",
-	Args:  cobra.ExactArgs(1),
+	Long: `Edita un job existente.
+
+Sin flags muestra una interfaz interactiva. Para uso desde scripts:
+  orgmcron edit backup --set schedule="@every 6h" --set timeout=2h
+  orgmcron edit backup --set 'commands=["rsync -a /a /b", "echo done"]'
+  orgmcron edit backup --set retry.max_attempts=3`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		jobName := args[0]
 
-		// Cargar job existente
-		existingJob, err := config.GetJobByName(jobName)
-		if err != nil {
-			return fmt.Errorf("error cargando job: %w", err)
+		var updatedJob *config.Job
+		switch {
+		case len(editSets) > 0:
+			// Se aplica bajo el lock de configuración sobre la versión en disco
+			err := config.ModifyJob(jobName, func(j *config.Job) error {
+				if err := applySetters(j, editSets); err != nil {
+					return err
+				}
+				if err := validateEditedJob(jobName, j); err != nil {
+					return err
+				}
+				updated := *j
+				updatedJob = &updated
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("error actualizando job: %w", err)
+			}
+		case isInteractive():
+			existingJob, err := config.GetJobByName(jobName)
+			if err != nil {
+				return fmt.Errorf("error cargando job: %w", err)
+			}
+			edited, err := editJobInteractive(existingJob)
+			if err != nil {
+				return err
+			}
+			// El formulario pudo tardar: solo se aplican sus campos sobre la
+			// versión en disco, así no se pierde un cambio hecho mientras tanto
+			// (ej. una pausa)
+			err = config.ModifyJob(jobName, func(j *config.Job) error {
+				j.Schedule = edited.Schedule
+				j.Commands = edited.Commands
+				j.HealthcheckURL = edited.HealthcheckURL
+				if err := validateEditedJob(jobName, j); err != nil {
+					return err
+				}
+				updated := *j
+				updatedJob = &updated
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("error actualizando job: %w", err)
+			}
+		default:
+			return fmt.Errorf("usa --set clave=valor para editar sin una terminal interactiva")
 		}
 
-		var (
-			scheduleType    string
-			cronExpr        string
-			intervalExpr    string
-			commandsStr     string
-			healthcheckName string
-		)
-
-		// Determinar tipo de schedule
-		if strings.HasPrefix(existingJob.Schedule, "@") {
-			scheduleType = "interval"
-			intervalExpr = existingJob.Schedule
-		} else {
-			scheduleType = "cron"
-			cronExpr = existingJob.Schedule
+		fmt.Printf("\n✓ Job '%s' actualizado exitosamente\n", jobName)
+		fmt.Printf("  Schedule: %s\n", scheduleSummary(updatedJob.Schedule))
+		fmt.Printf("  Comandos: %d\n", len(updatedJob.Commands))
+		if updatedJob.HealthcheckURL != "" {
+			fmt.Printf("  Healthcheck: %s\n", updatedJob.HealthcheckURL)
 		}
+		fmt.Println("\nEl daemon aplica los cambios automáticamente. Si se inició con --no-watch, ejecuta:")
+		fmt.Println("  orgmcron reload")
 
-		// Preparar comandos
-		commandsStr = strings.Join(existingJob.Commands, "\n")
+		return nil
+	},
+}
 
-		// Extraer nombre del healthcheck de la URL
-		if existingJob.HealthcheckURL != "" {
-			parts := strings.Split(existingJob.HealthcheckURL, "/")
-			if len(parts) > 0 {
-				healthcheckName = parts[len(parts)-1]
-			}
-		}
+// validateEditedJob verifica un job editado antes de guardarlo
+func validateEditedJob(name string, j *config.Job) error {
+	if j.Name != name {
+		return fmt.Errorf("no se puede cambiar el nombre de un job")
+	}
+	if err := validateSchedule(*j); err != nil {
+		return err
+	}
+	return validateJob(*j)
+}
 
-		// Primer formulario: tipo de schedule
-		form1 := huh.NewForm(
-			huh.NewGroup(
-				huh.NewSelect[string]().
-					Title("Tipo de schedule").
-					Description("Selecciona el tipo de programación").
-					Options(
-						huh.NewOption("Intervalo (@every)", "interval"),
-						huh.NewOption("Expresión Cron (* * * * *)", "cron"),
-					).
-					Value(&scheduleType),
-			),
-		)
-
-		if err := form1.Run(); err != nil {
-			return fmt.Errorf("error en el formulario: %w", err)
-		}
+// editJobInteractive edita un job con los formularios interactivos
+func editJobInteractive(existingJob *config.Job) (*config.Job, error) {
+	var (
+		scheduleType    string
+		cronExpr        string
+		intervalExpr    string
+		commandsStr     string
+		healthcheckName string
+	)
+
+	// Determinar tipo de schedule
+	if strings.HasPrefix(existingJob.Schedule, "@") {
+		scheduleType = "interval"
+		intervalExpr = existingJob.Schedule
+	} else {
+		scheduleType = "cron"
+		cronExpr = existingJob.Schedule
+	}
+
+	// Preparar comandos
+	commandsStr = config.CommandsText(existingJob.Commands)
+
+	// Extraer nombre del healthcheck de la URL; las URLs personalizadas se muestran completas
+	healthcheckName = strings.TrimPrefix(existingJob.HealthcheckURL, healthcheckBaseURL)
 
-		// Segundo formulario: schedule específico según el tipo
+	// Primer formulario: tipo de schedule
+	form1 := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Tipo de schedule").
+				Description("Selecciona el tipo de programación").
+				Options(
+					huh.NewOption("Intervalo (@every)", "interval"),
+					huh.NewOption("Expresión Cron (* * * * *)", "cron"),
+				).
+				Value(&scheduleType),
+		),
+	)
+
+	if err := form1.Run(); err != nil {
+		return nil, fmt.Errorf("error en el formulario: %w", err)
+	}
+
+	// El schedule se interpreta en la zona horaria que tendrá el job
+	appConfig, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error cargando configuración: %w", err)
+	}
+	timezone := appConfig.TimezoneFor(*existingJob)
+
+	// Segundo formulario: schedule específico según el tipo, hasta que se
+	// confirme su vista previa
+	var scheduleExpr string
+	for {
 		var form2 *huh.Form
 		if scheduleType == "cron" {
 			form2 = huh.NewForm(
@@ -82,7 +163,8 @@ var editCmd = &cobra.Command{
 							if s == "" {
 								return fmt.Errorf("la expresión cron es requerida")
 							}
-							return nil
+							_, err := schedule.ParseIn(s, timezone)
+							return err
 						}),
 				),
 			)
@@ -112,86 +194,66 @@ var editCmd = &cobra.Command{
 		}
 
 		if err := form2.Run(); err != nil {
-			return fmt.Errorf("error en el formulario: %w", err)
-		}
-
-		// Tercer formulario: comandos y healthcheck
-		form3 := huh.NewForm(
-			huh.NewGroup(
-				huh.NewText().
-					Title("Comandos").
-					Description("Un comando por línea. Se ejecutarán en orden.").
-					Value(&commandsStr).
-					Validate(func(s string) error {
-						if s == "" {
-							return fmt.Errorf("debe proporcionar al menos un comando")
-						}
-						return nil
-					}).
-					CharLimit(10000),
-
-				huh.NewInput().
-					Title("Nombre para healthcheck").
-					Description("Nombre del proyecto para el healthcheck (se construirá la URL automáticamente)").
-					Value(&healthcheckName).
-					Placeholder("prueba"),
-			),
-		)
-
-		if err := form3.Run(); err != nil {
-			return fmt.Errorf("error en el formulario: %w", err)
+			return nil, fmt.Errorf("error en el formulario: %w", err)
 		}
 
-		// Determinar schedule final
-		var schedule string
 		if scheduleType == "cron" {
-			schedule = cronExpr
+			scheduleExpr = cronExpr
 		} else {
-			schedule = intervalExpr
+			scheduleExpr = intervalExpr
 		}
-
-		// Parsear comandos
-		commandLines := strings.Split(commandsStr, "\n")
-		commands := []string{}
-		for _, line := range commandLines {
-			line = strings.TrimSpace(line)
-			if line != "" {
-				commands = append(commands, line)
-			}
+		confirmed, err := confirmSchedule(scheduleExpr, timezone)
+		if err != nil {
+			return nil, err
 		}
-
-		// Construir healthcheck URL
-		var healthcheckURL string
-		if healthcheckName != "" {
-			healthcheckURL = fmt.Sprintf("https://hc.or-gm.com/ping/{pingkey}/%s", healthcheckName)
+		if confirmed {
+			break
 		}
+	}
 
-		// Actualizar job
-		updatedJob := config.Job{
-			Name:           jobName,
-			Schedule:       schedule,
-			Commands:       commands,
-			HealthcheckURL: healthcheckURL,
-		}
+	// Tercer formulario: comandos y healthcheck
+	form3 := huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("Comandos").
+				Description("Un comando por línea. Se ejecutarán en orden.").
+				Value(&commandsStr).
+				Validate(func(s string) error {
+					if s == "" {
+						return fmt.Errorf("debe proporcionar al menos un comando")
+					}
+					return nil
+				}).
+				CharLimit(10000),
 
-		if err := config.UpdateJob(jobName, updatedJob); err != nil {
-			return fmt.Errorf("error actualizando job: %w", err)
-		}
+			huh.NewInput().
+				Title("Nombre para healthcheck").
+				Description("Nombre del proyecto para el healthcheck (se construirá la URL automáticamente)").
+				Value(&healthcheckName).
+				Placeholder("prueba"),
+		),
+	)
 
-		fmt.Printf("\n✓ Job '%s' actualizado exitosamente\n", jobName)
-		fmt.Printf("  Schedule: %s\n", schedule)
-		fmt.Printf("  Comandos: %d\n", len(commands))
-		if healthcheckURL != "" {
-			fmt.Printf("  Healthcheck: %s\n", healthcheckURL)
-		}
-		fmt.Println("\nPara aplicar los cambios, ejecuta:")
-		fmt.Println("  orgmcron reload")
+	if err := form3.Run(); err != nil {
+		return nil, fmt.Errorf("error en el formulario: %w", err)
+	}
 
-		return nil
-	},
+	// Parsear comandos conservando las opciones de los existentes
+	commands := config.CommandsFromText(commandsStr, existingJob.Commands)
+
+	// Construir healthcheck URL
+	healthcheckURL := healthcheckURLFromInput(healthcheckName)
+
+	// Actualizar job conservando los campos que no se editan en el formulario
+	updatedJob := *existingJob
+	updatedJob.Schedule = scheduleExpr
+	updatedJob.Commands = commands
+	updatedJob.HealthcheckURL = healthcheckURL
+
+	return &updatedJob, nil
 }
 
 func init() {
+	editCmd.Flags().StringArrayVar(&editSets, "set", nil, "Asigna un campo del job (clave=valor, repetible; ej. schedule=@daily)")
 	rootCmd.AddCommand(editCmd)
 }
-
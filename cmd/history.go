@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/osmargm1202/orgmcron/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit  int
+	historyStatus string
+	historySince  string
+	historyJSON   bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [job_name]",
+	Short: "Muestra el historial de ejecuciones",
+	Long: `Muestra el historial de ejecuciones de todos los jobs o de uno en particular.
+
+Ejemplos:
+  orgmcron history
+  orgmcron history backup --status failed --since 7d
+  orgmcron history backup --limit 1 --status success --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := history.Filter{Status: historyStatus, Limit: historyLimit}
+		if len(args) == 1 {
+			filter.Job = args[0]
+		}
+		if historySince != "" {
+			since, err := parseSince(historySince, time.Now())
+			if err != nil {
+				return err
+			}
+			filter.Since = since
+		}
+
+		records, err := history.Load(filter)
+		if err != nil {
+			return err
+		}
+
+		if historyJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(records)
+		}
+
+		if len(records) == 0 {
+			fmt.Println("No hay ejecuciones registradas.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "INICIO\tJOB\tDURACIÓN\tCÓDIGO\tESTADO\tINTENTO\tORIGEN\tHEALTHCHECK")
+		fmt.Fprintln(w, "------\t---\t--------\t------\t------\t-------\t------\t-----------")
+		for _, r := range records {
+			healthcheck := r.Healthcheck
+			if healthcheck == "" {
+				healthcheck = "-"
+			}
+			attempt := "-"
+			if r.Attempt > 0 {
+				attempt = fmt.Sprintf("%d/%d", r.Attempt, r.MaxAttempts)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+				r.Start.Local().Format("2006-01-02 15:04:05"), r.Job, formatSeconds(r.Duration), r.ExitCode, r.Status, attempt, r.Trigger, healthcheck)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "Máximo de ejecuciones a mostrar (las más recientes, 0 = todas)")
+	historyCmd.Flags().StringVar(&historyStatus, "status", "", "Filtra por estado (success, failed, timeout, canceled)")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Solo ejecuciones desde una antigüedad (24h, 7d) o fecha (2006-01-02)")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "Salida en formato JSON")
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"golang.org/x/term"
+)
+
+// healthcheckBaseURL es el prefijo usado cuando el healthcheck se indica por nombre
+const healthcheckBaseURL = "https://hc.or-gm.com/ping/{pingkey}/"
+
+// isInteractive indica si hay una terminal para mostrar formularios
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// healthcheckURLFromInput construye la URL del healthcheck a partir de un
+// nombre de proyecto. Si ya es una URL completa se usa tal cual.
+func healthcheckURLFromInput(input string) string {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return ""
+	}
+	if strings.Contains(input, "://") {
+		return input
+	}
+	return healthcheckBaseURL + input
+}
+
+// readJobFile lee la definición JSON de un job desde un archivo o desde stdin ("-")
+func readJobFile(path string) (*config.Job, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo definición del job: %w", err)
+	}
+
+	var j config.Job
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&j); err != nil {
+		return nil, fmt.Errorf("error parseando definición del job: %w", err)
+	}
+	return &j, nil
+}
+
+// applySetters aplica asignaciones clave=valor (ej. schedule=@daily,
+// retry.max_attempts=3) sobre un job. El valor se interpreta como JSON si es
+// válido para el campo y, si no, como string.
+func applySetters(j *config.Job, setters []string) error {
+	for _, setter := range setters {
+		key, raw, ok := strings.Cut(setter, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("asignación inválida '%s', se espera clave=valor", setter)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+
+		updated, err := setJobField(*j, key, value)
+		if err != nil {
+			if _, isString := value.(string); isString {
+				return fmt.Errorf("error asignando '%s': %w", key, err)
+			}
+			// Reintentar tratando el valor como texto (ej. timeout=10 no es un número válido)
+			if updated, err = setJobField(*j, key, raw); err != nil {
+				return fmt.Errorf("error asignando '%s': %w", key, err)
+			}
+		}
+		*j = updated
+	}
+	return nil
+}
+
+// setJobField asigna un valor a un campo (con notación a.b para campos anidados)
+func setJobField(j config.Job, key string, value interface{}) (config.Job, error) {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return j, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return j, err
+	}
+
+	parts := strings.Split(key, ".")
+	current := fields
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return j, err
+	}
+	var updated config.Job
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&updated); err != nil {
+		return j, err
+	}
+	return updated, nil
+}
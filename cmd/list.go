@@ -3,9 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"text/tabwriter"
+	"time"
 
 	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/schedule"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +21,10 @@ var listCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("error cargando jobs: %w", err)
 		}
+		appConfig, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("error cargando configuración: %w", err)
+		}
 
 		if len(jobsConfig.Jobs) == 0 {
 			fmt.Println("No hay jobs configurados.")
@@ -25,17 +32,40 @@ var listCmd = &cobra.Command{
 			return nil
 		}
 
+		// Con archivos en jobs.d se muestra de qué archivo viene cada job
+		showSource := false
+		for _, job := range jobsConfig.Jobs {
+			if filepath.Base(filepath.Dir(job.Source)) == config.JobsDir {
+				showSource = true
+				break
+			}
+		}
+
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "NOMBRE\tSCHEDULE\tCOMANDOS\tHEALTHCHECK")
-		fmt.Fprintln(w, "------\t--------\t--------\t-----------")
+		if showSource {
+			fmt.Fprintln(w, "NOMBRE\tSCHEDULE\tCOMANDOS\tHEALTHCHECK\tESTADO\tARCHIVO")
+			fmt.Fprintln(w, "------\t--------\t--------\t-----------\t------\t-------")
+		} else {
+			fmt.Fprintln(w, "NOMBRE\tSCHEDULE\tCOMANDOS\tHEALTHCHECK\tESTADO")
+			fmt.Fprintln(w, "------\t--------\t--------\t-----------\t------")
+		}
 
+		now := time.Now()
 		for _, job := range jobsConfig.Jobs {
 			commandsCount := fmt.Sprintf("%d", len(job.Commands))
 			healthcheck := "No"
 			if job.HealthcheckURL != "" {
 				healthcheck = "Sí"
 			}
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", job.Name, job.Schedule, commandsCount, healthcheck)
+			state := pauseDescription(job, now)
+			if state == "" {
+				state = "activo"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s", job.Name, scheduleLabel(job.Schedule, schedule.EffectiveTimezone(job.Schedule, appConfig.TimezoneFor(job))), commandsCount, healthcheck, state)
+			if showSource {
+				fmt.Fprintf(w, "\t%s", config.DisplayPath(job.Source))
+			}
+			fmt.Fprintln(w)
 		}
 
 		w.Flush()
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/scheduler"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pauseUntil  string
+	pauseReason string
+)
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause [job_name]",
+	Short: "Pausa un job sin eliminarlo",
+	Long: `Pausa un job: el daemon omite sus ejecuciones programadas hasta 'orgmcron resume'
+o hasta la hora indicada con --until. Las ejecuciones en curso no se interrumpen
+y 'orgmcron run' sigue funcionando.
+
+Ejemplos:
+  orgmcron pause backup --reason "mantenimiento del NAS"
+  orgmcron pause backup --until 2h
+  orgmcron pause backup --until "2026-11-01 08:00"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jobName := args[0]
+
+		var until time.Time
+		if pauseUntil != "" {
+			var err error
+			if until, err = parseUntil(pauseUntil, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		err := config.ModifyJob(jobName, func(j *config.Job) error {
+			enabled := false
+			j.Enabled = &enabled
+			j.PausedUntil = ""
+			if !until.IsZero() {
+				j.PausedUntil = until.Format(time.RFC3339)
+			}
+			j.PauseReason = pauseReason
+			return validateJob(*j)
+		})
+		if err != nil {
+			return fmt.Errorf("error guardando job: %w", err)
+		}
+
+		fmt.Printf("✓ Job '%s' pausado", jobName)
+		if !until.IsZero() {
+			fmt.Printf(" hasta %s", until.Format("2006-01-02 15:04:05"))
+		}
+		fmt.Println()
+		if pauseReason != "" {
+			fmt.Printf("  Motivo: %s\n", pauseReason)
+		}
+		applyToDaemon()
+		return nil
+	},
+}
+
+// applyToDaemon pide al daemon, si está corriendo, que aplique la
+// configuración de inmediato (aunque se haya iniciado con --no-watch)
+func applyToDaemon() {
+	client, err := scheduler.NewClient()
+	if err == nil {
+		_, err = client.Reload()
+	}
+	switch {
+	case err == nil:
+		fmt.Println("  Cambio aplicado en el daemon")
+	case errors.Is(err, scheduler.ErrAPIUnavailable):
+		fmt.Println("  El daemon no está corriendo; el cambio se aplicará al iniciarlo")
+	default:
+		fmt.Printf("  Advertencia: no se pudo aplicar en el daemon: %v\n", err)
+	}
+}
+
+func init() {
+	pauseCmd.Flags().StringVar(&pauseUntil, "until", "", "Reanuda automáticamente tras un plazo (2h, 3d) o en una fecha (2006-01-02 15:04)")
+	pauseCmd.Flags().StringVar(&pauseReason, "reason", "", "Motivo de la pausa (se muestra en list y status)")
+	rootCmd.AddCommand(pauseCmd)
+}
+
+// pauseDescription describe la pausa de un job para list y status; vacío si
+// no está pausado
+func pauseDescription(j config.Job, now time.Time) string {
+	if !j.IsPaused(now) {
+		return ""
+	}
+	description := "pausado"
+	if until, err := j.PausedUntilTime(); err == nil && !until.IsZero() {
+		description += " hasta " + until.Local().Format("2006-01-02 15:04")
+	}
+	if j.PauseReason != "" {
+		description += " (" + j.PauseReason + ")"
+	}
+	return description
+}
@@ -1,41 +1,67 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"syscall"
 
+	"github.com/osmargm1202/orgmcron/internal/scheduler"
 	"github.com/osmargm1202/orgmcron/internal/service"
 	"github.com/spf13/cobra"
 )
 
 var reloadCmd = &cobra.Command{
 	Use:   "reload",
-	Short: "Recarga la configuración y reinicia el servicio",
-	Long:  "Recarga la configuración de jobs y reinicia el servicio systemd si está corriendo",
+	Short: "Recarga la configuración del daemon",
+	Long:  "Pide al daemon que recargue los jobs sin interrumpir las ejecuciones en curso y muestra el resumen de cambios",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Verificar si el servicio existe
-		if !service.ServiceExists() {
-			fmt.Println("El servicio no está instalado. Ejecuta 'orgmcron install' primero.")
-			return nil
-		}
+		return reloadService()
+	},
+}
 
-		// Verificar si el servicio está corriendo
-		if service.IsServiceRunning() {
-			fmt.Println("Reiniciando servicio systemd...")
-			if err := service.RestartService(); err != nil {
-				return fmt.Errorf("error reiniciando servicio: %w", err)
+// reloadService pide al daemon en ejecución que recargue la configuración.
+// Usa la API de control para mostrar el resumen y, si no está disponible,
+// envía SIGHUP.
+func reloadService() error {
+	client, err := scheduler.NewClient()
+	if err == nil {
+		var summary *scheduler.ReloadSummary
+		if summary, err = client.Reload(); err == nil {
+			fmt.Println("✓ Configuración recargada (las ejecuciones en curso no se interrumpen)")
+			fmt.Printf("  %s\n", summary)
+			for name, reason := range summary.Failed {
+				fmt.Printf("  ✗ %s: %s\n", name, reason)
 			}
-			fmt.Println("✓ Servicio reiniciado exitosamente")
-		} else {
-			fmt.Println("El servicio no está corriendo. Los cambios se aplicarán cuando se inicie el servicio.")
-			fmt.Println("Para iniciar el servicio, ejecuta:")
-			fmt.Println("  systemctl --user start orgmcron")
+			return nil
+		}
+		if !errors.Is(err, scheduler.ErrAPIUnavailable) {
+			return fmt.Errorf("error recargando daemon: %w", err)
 		}
+	}
 
+	err = service.SignalDaemon(syscall.SIGHUP)
+	if err == nil {
+		fmt.Println("✓ Recarga solicitada al daemon (las ejecuciones en curso no se interrumpen)")
+		fmt.Println("  El resumen de cambios aparece en la salida del daemon:")
+		fmt.Println("  journalctl --user -u orgmcron -n 20")
 		return nil
-	},
+	}
+	if !errors.Is(err, service.ErrDaemonNotRunning) {
+		return fmt.Errorf("error recargando daemon: %w", err)
+	}
+
+	// Verificar si el servicio existe
+	if !service.ServiceExists() {
+		fmt.Println("El daemon no está corriendo y el servicio no está instalado. Ejecuta 'orgmcron install' primero.")
+		return nil
+	}
+
+	fmt.Println("El servicio no está corriendo. Los cambios se aplicarán cuando se inicie el servicio.")
+	fmt.Println("Para iniciar el servicio, ejecuta:")
+	fmt.Println("  systemctl --user start orgmcron")
+	return nil
 }
 
 func init() {
 	rootCmd.AddCommand(reloadCmd)
 }
-
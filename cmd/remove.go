@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/job"
+	"github.com/spf13/cobra"
+)
+
+var (
+	removeYes  bool
+	removeLogs string
+)
+
+var removeCmd = &cobra.Command{
+	Use:     "remove [job_name]...",
+	Aliases: []string{"rm"},
+	Short:   "Elimina uno o más jobs",
+	Long: `Elimina uno o más jobs de la configuración y recarga el servicio.
+
+Con --logs se decide qué hacer con el log de cada job:
+  keep     conserva <job>.log (default)
+  archive  lo mueve a logs/archive/<job>-<fecha>.log
+  delete   lo elimina`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch removeLogs {
+		case "keep", "archive", "delete":
+		default:
+			return fmt.Errorf("valor inválido para --logs: %s (usa keep, archive o delete)", removeLogs)
+		}
+
+		// Verificar que todos los jobs existan antes de eliminar ninguno
+		for _, name := range args {
+			if _, err := config.GetJobByName(name); err != nil {
+				return err
+			}
+		}
+
+		if !removeYes {
+			if !isInteractive() {
+				return fmt.Errorf("usa --yes para confirmar la eliminación sin una terminal interactiva")
+			}
+			confirmed := false
+			confirm := huh.NewConfirm().
+				Title(fmt.Sprintf("¿Eliminar %s?", strings.Join(args, ", "))).
+				Description(fmt.Sprintf("Logs: %s", removeLogs)).
+				Affirmative("Eliminar").
+				Negative("Cancelar").
+				Value(&confirmed)
+			if err := confirm.Run(); err != nil {
+				return fmt.Errorf("error en el formulario: %w", err)
+			}
+			if !confirmed {
+				fmt.Println("Cancelado.")
+				return nil
+			}
+		}
+
+		if err := config.DeleteJobs(args); err != nil {
+			return fmt.Errorf("error eliminando jobs: %w", err)
+		}
+
+		// Los jobs ya no están: un error con un log no impide tratar los
+		// demás ni recargar el servicio
+		var errs []error
+		for _, name := range args {
+			fmt.Printf("✓ Job '%s' eliminado\n", name)
+
+			switch removeLogs {
+			case "archive":
+				archivePath, err := job.ArchiveLog(name)
+				if err != nil {
+					errs = append(errs, err)
+					fmt.Printf("  Error archivando log: %v\n", err)
+				} else if archivePath != "" {
+					fmt.Printf("  Log archivado en %s\n", archivePath)
+				}
+			case "delete":
+				if err := job.DeleteLog(name); err != nil {
+					errs = append(errs, err)
+					fmt.Printf("  Error eliminando log: %v\n", err)
+				} else {
+					fmt.Println("  Log eliminado")
+				}
+			}
+		}
+
+		fmt.Println()
+		reloadErr := reloadService()
+		if len(errs) > 0 {
+			// Cada error ya se mostró junto a su job
+			return errors.Join(fmt.Errorf("no se pudieron procesar %d log(s)", len(errs)), reloadErr)
+		}
+		return reloadErr
+	},
+}
+
+func init() {
+	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "No pedir confirmación")
+	removeCmd.Flags().StringVar(&removeLogs, "logs", "keep", "Qué hacer con los logs: keep, archive o delete")
+	rootCmd.AddCommand(removeCmd)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume [job_name]",
+	Short: "Reanuda un job pausado",
+	Long:  "Reanuda un job pausado con 'orgmcron pause' (o desde la API del daemon)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jobName := args[0]
+
+		err := config.ModifyJob(jobName, func(j *config.Job) error {
+			j.Enabled = nil
+			j.PausedUntil = ""
+			j.PauseReason = ""
+			return validateJob(*j)
+		})
+		if err != nil {
+			return fmt.Errorf("error guardando job: %w", err)
+		}
+
+		fmt.Printf("✓ Job '%s' reanudado\n", jobName)
+		applyToDaemon()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resumeCmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/history"
+	"github.com/osmargm1202/orgmcron/internal/job"
+	"github.com/osmargm1202/orgmcron/internal/scheduler"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runStream        bool
+	runNoHealthcheck bool
+	runDaemon        bool
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [job_name]",
+	Short: "Ejecuta un job inmediatamente",
+	Long: `Ejecuta un job ahora mismo con la misma lógica que una ejecución programada
+(log del job, reintentos y healthcheck).
+
+Por defecto se ejecuta en este proceso. Con --daemon se le pide al daemon que
+lo ejecute (a través de su socket de control), de modo que se respete su
+política de concurrencia.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jobName := args[0]
+
+		j, err := config.GetJobByName(jobName)
+		if err != nil {
+			return err
+		}
+
+		if runDaemon {
+			if runStream {
+				return fmt.Errorf("--stream no está disponible con --daemon; usa 'orgmcron log %s'", jobName)
+			}
+			client, err := scheduler.NewClient()
+			if err == nil {
+				err = client.RunNow(jobName, runNoHealthcheck)
+			}
+			if errors.Is(err, scheduler.ErrAPIUnavailable) {
+				return fmt.Errorf("el daemon no está corriendo; ejecuta sin --daemon o inicia el servicio")
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✓ Ejecución de '%s' solicitada al daemon\n", jobName)
+			fmt.Printf("  Para ver la salida: orgmcron log %s\n", jobName)
+			return nil
+		}
+
+		appConfig, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("error cargando configuración: %w", err)
+		}
+
+		// Ctrl+C termina el grupo de procesos del comando en curso
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		opts := scheduler.RunOptions{SkipHealthcheck: runNoHealthcheck, Trigger: history.TriggerManual}
+		if runStream {
+			opts.Stream = os.Stdout
+		}
+
+		result, err := scheduler.Run(ctx, *j, appConfig.PingKey, opts)
+		if err != nil {
+			return err
+		}
+
+		logPath, _ := job.GetLogPath(jobName)
+		fmt.Printf("\nJob '%s' finalizado: %s (código %d)\n", jobName, result.Status, result.ExitCode)
+		fmt.Printf("  Log: %s\n", logPath)
+
+		if result.ExitCode != 0 {
+			os.Exit(result.ExitCode)
+		}
+		return nil
+	},
+}
+
+func init() {
+	runCmd.Flags().BoolVar(&runStream, "stream", false, "Muestra la salida en la terminal además de guardarla en el log")
+	runCmd.Flags().BoolVar(&runNoHealthcheck, "no-healthcheck", false, "No envía pings al healthcheck")
+	runCmd.Flags().BoolVar(&runDaemon, "daemon", false, "Pide al daemon en ejecución que ejecute el job")
+	rootCmd.AddCommand(runCmd)
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/schedule"
+	"github.com/spf13/cobra"
+)
+
+// previewRuns es la cantidad de próximas ejecuciones que se muestran al validar un schedule
+const previewRuns = 5
+
+var (
+	explainTimezone string
+	explainCount    int
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Herramientas para expresiones de schedule",
+}
+
+var scheduleExplainCmd = &cobra.Command{
+	Use:   "explain <expresión>",
+	Short: "Valida un schedule y muestra cuándo se ejecutaría",
+	Long: `Valida una expresión de schedule con el mismo parser que el daemon, la
+describe en lenguaje natural y muestra las próximas ejecuciones.
+
+Ejemplos:
+  orgmcron schedule explain "30 8 * * 1-5"
+  orgmcron schedule explain "@every 90m"
+  orgmcron schedule explain "0 9 * * *" --timezone America/Santo_Domingo`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if explainCount < 1 {
+			return fmt.Errorf("--count debe ser al menos 1")
+		}
+		timezone := explainTimezone
+		if timezone == "" {
+			appConfig, err := config.LoadConfig()
+			if err != nil {
+				return fmt.Errorf("error cargando configuración: %w", err)
+			}
+			timezone = appConfig.Timezone
+		}
+
+		preview, err := schedulePreview(args[0], timezone, explainCount)
+		if err != nil {
+			return err
+		}
+		fmt.Print(preview)
+		return nil
+	},
+}
+
+// schedulePreview describe un schedule y lista sus próximas n ejecuciones
+func schedulePreview(expr, timezone string, n int) (string, error) {
+	if _, err := schedule.ParseIn(expr, timezone); err != nil {
+		return "", err
+	}
+	desc, err := schedule.Describe(expr)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	runs, err := schedule.Next(expr, timezone, now, n)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Schedule:    %s\n", expr)
+	if tz := schedule.EffectiveTimezone(expr, timezone); tz != "" {
+		fmt.Fprintf(&b, "Zona:        %s\n", tz)
+	}
+	fmt.Fprintf(&b, "Descripción: %s\n", desc)
+	fmt.Fprintf(&b, "\nPróximas ejecuciones:\n")
+	for _, run := range runs {
+		fmt.Fprintf(&b, "  %s (en %s)\n", run.Format("2006-01-02 15:04:05 MST"), run.Sub(now).Round(time.Second))
+	}
+	return b.String(), nil
+}
+
+// validateSchedule verifica que el schedule de un job sea válido en su zona horaria
+func validateSchedule(j config.Job) error {
+	appConfig, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error cargando configuración: %w", err)
+	}
+	_, err = schedule.ParseIn(j.Schedule, appConfig.TimezoneFor(j))
+	return err
+}
+
+// scheduleSummary muestra un schedule junto a su descripción
+func scheduleSummary(expr string) string {
+	desc, err := schedule.Describe(expr)
+	if err != nil {
+		return expr
+	}
+	return fmt.Sprintf("%s (%s)", expr, desc)
+}
+
+// confirmSchedule muestra la vista previa de un schedule en el formulario y
+// pregunta si se usa
+func confirmSchedule(expr, timezone string) (bool, error) {
+	preview, err := schedulePreview(expr, timezone, previewRuns)
+	if err != nil {
+		return false, err
+	}
+	confirmed := true
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("¿Usar este schedule?").
+				Description(preview).
+				Affirmative("Sí").
+				Negative("No, cambiarlo").
+				Value(&confirmed),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return false, fmt.Errorf("error en el formulario: %w", err)
+	}
+	return confirmed, nil
+}
+
+func init() {
+	scheduleExplainCmd.Flags().StringVar(&explainTimezone, "timezone", "", "Zona horaria en la que se interpreta (por defecto la de config.json o la local)")
+	scheduleExplainCmd.Flags().IntVarP(&explainCount, "count", "n", previewRuns, "Cantidad de próximas ejecuciones a mostrar")
+	scheduleCmd.AddCommand(scheduleExplainCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/osmargm1202/orgmcron/internal/config"
 	"github.com/osmargm1202/orgmcron/internal/scheduler"
+	"github.com/osmargm1202/orgmcron/internal/service"
 	"github.com/spf13/cobra"
 )
 
+var startNoWatch bool
+
 var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Inicia el daemon que ejecuta los jobs",
@@ -24,6 +28,15 @@ var startCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "Advertencia: pingkey no configurado. Usa 'orgmcron config pingkey <key>' para configurarlo.\n")
 		}
 
+		// Un segundo daemon ejecutaría cada job dos veces y al salir borraría
+		// el archivo PID del primero
+		if err := service.LockDaemon(); err != nil {
+			if errors.Is(err, service.ErrDaemonRunning) {
+				return fmt.Errorf("%w; detenlo antes de iniciar otro (o usa 'orgmcron status')", err)
+			}
+			return err
+		}
+
 		// Crear scheduler
 		sched := scheduler.NewScheduler(appConfig.PingKey)
 		
@@ -31,6 +44,24 @@ var startCmd = &cobra.Command{
 		fmt.Printf("PingKey configurado: %s\n", appConfig.PingKey)
 		fmt.Println("Presiona Ctrl+C para detener el daemon")
 
+		// Registrar el PID para que 'orgmcron reload' pueda enviar SIGHUP
+		if err := service.WritePIDFile(); err != nil {
+			fmt.Fprintf(os.Stderr, "Advertencia: %v\n", err)
+		}
+		defer service.RemovePIDFile()
+
+		// API de control para la CLI (status, run --daemon, reload...)
+		if err := sched.ServeAPI(); err != nil {
+			fmt.Fprintf(os.Stderr, "Advertencia: API de control no disponible: %v\n", err)
+		}
+
+		// Recargar automáticamente cuando cambie la configuración
+		if !startNoWatch {
+			if err := sched.Watch(); err != nil {
+				fmt.Fprintf(os.Stderr, "Advertencia: no se pudo observar la configuración: %v\n", err)
+			}
+		}
+
 		// Iniciar scheduler (bloquea hasta recibir señal de parada)
 		if err := sched.Start(); err != nil {
 			return fmt.Errorf("error iniciando scheduler: %w", err)
@@ -42,6 +73,7 @@ var startCmd = &cobra.Command{
 }
 
 func init() {
+	startCmd.Flags().BoolVar(&startNoWatch, "no-watch", false, "No recargar automáticamente al cambiar los jobs o config.json")
 	rootCmd.AddCommand(startCmd)
 }
 
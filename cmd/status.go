@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/history"
+	"github.com/osmargm1202/orgmcron/internal/schedule"
+	"github.com/osmargm1202/orgmcron/internal/scheduler"
+	"github.com/osmargm1202/orgmcron/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var statusJSON bool
+
+// jobStatus es el estado de un job mostrado por `orgmcron status`
+type jobStatus struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	Timezone string `json:"timezone,omitempty"`
+	Running  bool   `json:"running"`
+	Paused   bool   `json:"paused"`
+	// PauseInfo describe la pausa configurada (hasta cuándo y motivo)
+	PauseInfo string          `json:"pause_info,omitempty"`
+	LastRun   *history.Record `json:"last_run,omitempty"`
+	NextRun   *time.Time      `json:"next_run,omitempty"`
+	// Scheduled indica si el daemon tiene el job programado
+	Scheduled bool   `json:"scheduled"`
+	Error     string `json:"error,omitempty"`
+}
+
+// daemonStatus es el estado del daemon mostrado por `orgmcron status`
+type daemonStatus struct {
+	Running   bool       `json:"running"`
+	PID       int        `json:"pid,omitempty"`
+	Service   bool       `json:"service"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Muestra el estado del daemon y de cada job",
+	Long: `Muestra si el daemon está corriendo y, por cada job, si se está ejecutando,
+el resultado de su última ejecución y cuándo será la próxima.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jobsConfig, err := config.LoadJobs()
+		if err != nil {
+			return fmt.Errorf("error cargando jobs: %w", err)
+		}
+		appConfig, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("error cargando configuración: %w", err)
+		}
+		lastRuns, err := history.LastByJob()
+		if err != nil {
+			return err
+		}
+
+		daemon := daemonStatus{Service: service.IsServiceRunning()}
+		daemon.PID, daemon.Running = service.DaemonPID()
+		daemon.Running = daemon.Running || daemon.Service
+
+		// El daemon informa en vivo qué tiene programado y cuándo será la
+		// próxima ejecución de cada job
+		entries := map[string]scheduler.EntryStatus{}
+		live := false
+		if client, err := scheduler.NewClient(); err == nil {
+			if remote, err := client.Status(); err == nil {
+				live = true
+				daemon.Running = true
+				daemon.PID = remote.PID
+				daemon.StartedAt = &remote.StartedAt
+				for _, e := range remote.Entries {
+					entries[e.Job] = e
+				}
+			} else if !errors.Is(err, scheduler.ErrAPIUnavailable) {
+				fmt.Fprintf(os.Stderr, "Advertencia: %v\n", err)
+			}
+		}
+
+		now := time.Now()
+		statuses := make([]jobStatus, 0, len(jobsConfig.Jobs))
+		for _, j := range jobsConfig.Jobs {
+			st := jobStatus{Name: j.Name, Schedule: j.Schedule, Timezone: schedule.EffectiveTimezone(j.Schedule, appConfig.TimezoneFor(j)), Running: scheduler.IsRunning(j.Name)}
+			st.PauseInfo = pauseDescription(j, now)
+			st.Paused = st.PauseInfo != ""
+			if last, ok := lastRuns[j.Name]; ok {
+				st.LastRun = &last
+			}
+			sched, err := schedule.ParseIn(j.Schedule, st.Timezone)
+			if err != nil {
+				st.Error = err.Error()
+			}
+			if entry, ok := entries[j.Name]; ok {
+				st.Scheduled = true
+				st.Running = st.Running || entry.Running > 0
+				st.Paused = st.Paused || entry.Paused
+				st.NextRun = entry.Next
+			} else if sched != nil && daemon.Running && !live {
+				// Daemon sin API (ej. no pudo crear el socket): se calcula
+				// a partir del schedule
+				if next := sched.Next(now); !next.IsZero() {
+					st.NextRun = &next
+				}
+			}
+			statuses = append(statuses, st)
+		}
+
+		if statusJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(struct {
+				Daemon daemonStatus `json:"daemon"`
+				Jobs   []jobStatus  `json:"jobs"`
+			}{daemon, statuses})
+		}
+
+		switch {
+		case daemon.StartedAt != nil:
+			fmt.Printf("Daemon: corriendo (PID %d, desde hace %s)\n\n", daemon.PID, now.Sub(*daemon.StartedAt).Round(time.Second))
+		case daemon.PID != 0:
+			fmt.Printf("Daemon: corriendo (PID %d)\n\n", daemon.PID)
+		case daemon.Service:
+			fmt.Printf("Daemon: corriendo (servicio systemd)\n\n")
+		default:
+			fmt.Printf("Daemon: detenido (los jobs no se ejecutarán hasta iniciarlo)\n\n")
+		}
+
+		if len(statuses) == 0 {
+			fmt.Println("No hay jobs configurados.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NOMBRE\tSCHEDULE\tESTADO\tÚLTIMA EJECUCIÓN\tRESULTADO\tDURACIÓN\tPRÓXIMA")
+		fmt.Fprintln(w, "------\t--------\t------\t----------------\t---------\t--------\t-------")
+		for _, st := range statuses {
+			state := "inactivo"
+			switch {
+			case st.Running:
+				state = "ejecutando"
+			case st.PauseInfo != "":
+				state = st.PauseInfo
+			case st.Paused:
+				state = "pausado"
+			}
+			lastRun, result, duration := "-", "-", "-"
+			if st.LastRun != nil {
+				lastRun = st.LastRun.Start.Local().Format("2006-01-02 15:04:05")
+				result = fmt.Sprintf("%s (%d)", st.LastRun.Status, st.LastRun.ExitCode)
+				duration = formatSeconds(st.LastRun.Duration)
+			}
+			next := "-"
+			switch {
+			case st.Error != "":
+				next = "schedule inválido"
+			case live && !st.Scheduled:
+				next = "no programado (ver salida del daemon)"
+			case st.Paused:
+				next = "- (pausado)"
+			case st.NextRun != nil:
+				// En la zona horaria del job, que es en la que se lee su schedule
+				loc, err := schedule.LoadLocation(st.Timezone)
+				if err != nil {
+					loc = time.Local
+				}
+				next = fmt.Sprintf("%s (en %s)", st.NextRun.In(loc).Format("2006-01-02 15:04:05 MST"), st.NextRun.Sub(now).Round(time.Second))
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", st.Name, scheduleLabel(st.Schedule, st.Timezone), state, lastRun, result, duration, next)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+// scheduleLabel muestra un schedule con su zona horaria, si tiene y no la
+// indica ya la propia expresión (CRON_TZ=...)
+func scheduleLabel(expr, timezone string) string {
+	if timezone == "" || schedule.EffectiveTimezone(expr, "") != "" {
+		return expr
+	}
+	return fmt.Sprintf("%s (%s)", expr, timezone)
+}
+
+// formatSeconds muestra una duración en segundos redondeada a una precisión legible
+func formatSeconds(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second))
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Salida en formato JSON")
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts son los formatos de fecha aceptados por --since y --until
+var dateLayouts = []string{"2006-01-02", "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", time.RFC3339}
+
+// parseSince interpreta --since como una antigüedad (30m, 24h, 7d) o una
+// fecha (2006-01-02 o 2006-01-02T15:04:05)
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, ok := parseRelative(value); ok {
+		return now.Add(-d), nil
+	}
+	if t, ok := parseDate(value); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("--since inválido '%s': usa una antigüedad (30m, 24h, 7d) o una fecha (2006-01-02)", value)
+}
+
+// parseUntil interpreta --until como un plazo desde ahora (30m, 2h, 3d) o una
+// fecha futura (2006-01-02 15:04)
+func parseUntil(value string, now time.Time) (time.Time, error) {
+	t, ok := parseDate(value)
+	if d, relative := parseRelative(value); relative {
+		t, ok = now.Add(d), true
+	}
+	if !ok {
+		return time.Time{}, fmt.Errorf("--until inválido '%s': usa un plazo (2h, 3d) o una fecha (2006-01-02 15:04)", value)
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("--until debe estar en el futuro: %s", t.Format("2006-01-02 15:04:05"))
+	}
+	return t, nil
+}
+
+// parseRelative interpreta una duración de Go o un número de días (7d)
+func parseRelative(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Duration(n) * 24 * time.Hour, true
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, true
+	}
+	return 0, false
+}
+
+// parseDate interpreta una fecha en hora local
+func parseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/validate"
+	"github.com/spf13/cobra"
+)
+
+var validatePrintSchema bool
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [archivo...]",
+	Short: "Valida la configuración",
+	Long: `Valida config.json, el archivo de jobs y los de jobs.d sin aplicarlos:
+sintaxis (con línea y columna), campos desconocidos, nombres duplicados o
+inválidos, schedules, zonas horarias y URLs de healthcheck. El daemon hace la
+misma validación antes de cada recarga.
+
+Con archivos como argumento valida solo esos archivos de jobs (ej. antes de
+copiarlos a jobs.d).
+
+Ejemplos:
+  orgmcron validate
+  orgmcron validate nuevo-job.yaml
+  orgmcron validate --print-schema > ~/.config/orgmcron/jobs.schema.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if validatePrintSchema {
+			os.Stdout.Write(validate.Schema)
+			return nil
+		}
+
+		var report *validate.Report
+		var err error
+		if len(args) > 0 {
+			report, err = validate.CheckFiles(args)
+		} else {
+			report, err = validate.Check()
+		}
+		if err != nil {
+			return fmt.Errorf("error validando configuración: %w", err)
+		}
+
+		for _, issue := range report.Issues {
+			if issue.Warning {
+				fmt.Printf("⚠ %s\n", issue)
+			} else {
+				fmt.Printf("✗ %s\n", issue)
+			}
+		}
+
+		errs := len(report.Errors())
+		if errs > 0 {
+			fmt.Printf("\nConfiguración inválida: %d error(es) en %d archivo(s)\n", errs, report.Files)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Configuración válida: %d job(s) en %d archivo(s)\n", report.Jobs, report.Files)
+		return nil
+	},
+}
+
+// validateJob valida un job antes de guardarlo, con las mismas reglas que
+// 'orgmcron validate' y el daemon
+func validateJob(j config.Job) error {
+	if err := validate.Job(j); err != nil {
+		return fmt.Errorf("job inválido: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	validateCmd.Flags().BoolVar(&validatePrintSchema, "print-schema", false, "Muestra el JSON Schema de los archivos de jobs")
+	rootCmd.AddCommand(validateCmd)
+}
@@ -3,9 +3,14 @@ module github.com/osmargm1202/orgmcron
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/charmbracelet/huh v0.3.0
+	github.com/fsnotify/fsnotify v1.9.0
 	github.com/robfig/cron/v3 v3.0.1
 	github.com/spf13/cobra v1.8.0
+	golang.org/x/sys v0.13.0
+	golang.org/x/term v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -28,7 +33,5 @@ require (
 	github.com/rivo/uniseg v0.4.6 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	golang.org/x/sync v0.4.0 // indirect
-	golang.org/x/sys v0.13.0 // indirect
-	golang.org/x/term v0.13.0 // indirect
 	golang.org/x/text v0.13.0 // indirect
 )
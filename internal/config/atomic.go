@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockFile es el archivo (en el directorio de configuración) con el que se
+// serializan entre procesos las modificaciones de la configuración. Está junto
+// a la configuración y no en el directorio de ejecución, que depende de
+// XDG_RUNTIME_DIR: dos procesos con distinto entorno (ej. el servicio systemd
+// y un script de cron) deben usar el mismo lock.
+const lockFile = ".config.lock"
+
+// withLock ejecuta fn con un flock exclusivo sobre la configuración, para que
+// dos procesos (ej. dos `orgmcron add` a la vez) no pierdan cambios en el
+// ciclo leer-modificar-guardar. El kernel libera el lock si el proceso muere.
+func withLock(fn func() error) error {
+	if err := EnsureConfigDir(); err != nil {
+		return err
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filepath.Join(configDir, lockFile), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("error abriendo lock de configuración: %w", err)
+	}
+	defer file.Close()
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("error tomando lock de configuración: %w", err)
+	}
+	defer syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+
+	return fn()
+}
+
+// writeFileAtomic escribe un archivo sin dejarlo nunca a medias: escribe un
+// temporal en el mismo directorio, lo sincroniza a disco y lo renombra sobre
+// el original. Si el archivo es un enlace simbólico se reemplaza su destino.
+// Se conservan los permisos del archivo existente.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(path)
+	// Oculto, para que ni el watcher ni jobs.d lo tomen como configuración
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	committed = true
+
+	// Sincronizar el directorio para que el rename sobreviva a un corte de luz
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// BackupsDir es el directorio (dentro del de configuración) con los
+	// snapshots de la configuración anteriores a cada cambio
+	BackupsDir = "backups"
+	// DefaultBackupRetention es la cantidad de snapshots conservados por defecto
+	DefaultBackupRetention = 20
+	// SnapshotRestore marca los snapshots tomados antes de restaurar otro
+	// snapshot con RestoreSnapshot
+	SnapshotRestore = "restore"
+	// SnapshotUndo marca los snapshots tomados antes de un Undo
+	SnapshotUndo = "undo"
+
+	snapshotManifest = "snapshot.json"
+	snapshotIDLayout = "20060102-150405.000"
+)
+
+// Snapshot es una copia de la configuración (config.json, el archivo de jobs
+// y jobs.d) tal como estaba antes de un cambio
+type Snapshot struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	// Command es el comando de orgmcron que hizo el cambio
+	Command string `json:"command,omitempty"`
+	// Kind es SnapshotRestore o SnapshotUndo si se tomó antes de restaurar
+	// otro snapshot
+	Kind string `json:"kind,omitempty"`
+	// Files son las rutas copiadas, relativas al directorio de configuración
+	Files []string `json:"files"`
+
+	dir string
+}
+
+// ReadFile lee un archivo del snapshot por su ruta relativa
+func (s *Snapshot) ReadFile(rel string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(rel)))
+}
+
+// HasFile indica si el snapshot contiene un archivo
+func (s *Snapshot) HasFile(rel string) bool {
+	for _, f := range s.Files {
+		if f == rel {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBackupsPath retorna la ruta del directorio de snapshots
+func GetBackupsPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, BackupsDir), nil
+}
+
+// ConfigFiles retorna los archivos de configuración existentes, relativos al
+// directorio de configuración: config.json, el archivo de jobs y los de jobs.d
+func ConfigFiles() ([]string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, name := range append([]string{ConfigFile}, JobsFileNames...) {
+		if info, err := os.Stat(filepath.Join(configDir, name)); err == nil && !info.IsDir() {
+			files = append(files, name)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(configDir, JobsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error leyendo %s: %w", JobsDir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !IsJobsDropIn(entry.Name()) {
+			continue
+		}
+		files = append(files, JobsDir+"/"+entry.Name())
+	}
+	return files, nil
+}
+
+// ListSnapshots retorna los snapshots guardados, del más reciente al más antiguo
+func ListSnapshots() ([]*Snapshot, error) {
+	backupsDir, err := GetBackupsPath()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(backupsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error leyendo %s: %w", BackupsDir, err)
+	}
+
+	var snapshots []*Snapshot
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		// Sin manifiesto el snapshot quedó a medias y se ignora
+		snapshot, err := readSnapshot(filepath.Join(backupsDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].ID > snapshots[j].ID
+	})
+	return snapshots, nil
+}
+
+// GetSnapshot busca un snapshot por su ID o por un prefijo único de él
+func GetSnapshot(id string) (*Snapshot, error) {
+	snapshots, err := ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	var found *Snapshot
+	for _, s := range snapshots {
+		if s.ID == id {
+			return s, nil
+		}
+		if strings.HasPrefix(s.ID, id) {
+			if found != nil {
+				return nil, fmt.Errorf("'%s' coincide con más de un snapshot", id)
+			}
+			found = s
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("snapshot '%s' no encontrado (ver 'orgmcron config history')", id)
+	}
+	return found, nil
+}
+
+// RestoreSnapshot deja la configuración como estaba en el snapshot. Antes se
+// guarda un snapshot del estado actual, así Undo puede deshacer la
+// restauración.
+func RestoreSnapshot(id string) (*Snapshot, error) {
+	var snapshot *Snapshot
+	err := withLock(func() error {
+		var err error
+		snapshot, err = GetSnapshot(id)
+		if err != nil {
+			return err
+		}
+		return restoreSnapshot(snapshot, SnapshotRestore)
+	})
+	return snapshot, err
+}
+
+// Undo deshace el último cambio (incluida una restauración) restaurando el
+// snapshot más reciente que no sea de otro Undo, y lo descarta para que un
+// nuevo Undo retroceda un cambio más. El estado deshecho queda en un snapshot
+// de tipo SnapshotUndo.
+func Undo() (*Snapshot, error) {
+	var snapshot *Snapshot
+	err := withLock(func() error {
+		snapshots, err := ListSnapshots()
+		if err != nil {
+			return err
+		}
+		for _, s := range snapshots {
+			if s.Kind != SnapshotUndo {
+				snapshot = s
+				break
+			}
+		}
+		if snapshot == nil {
+			return fmt.Errorf("no hay cambios para deshacer")
+		}
+		if err := restoreSnapshot(snapshot, SnapshotUndo); err != nil {
+			return err
+		}
+		return os.RemoveAll(snapshot.dir)
+	})
+	return snapshot, err
+}
+
+// restoreSnapshot es RestoreSnapshot sin tomar el lock, para usar dentro de
+// withLock; kind es el tipo del snapshot que guarda el estado actual
+func restoreSnapshot(snapshot *Snapshot, kind string) error {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+	current, err := ConfigFiles()
+	if err != nil {
+		return err
+	}
+	// Se lee antes de tomar el snapshot nuevo, que puede podar este
+	contents := make(map[string][]byte)
+	for _, rel := range snapshot.Files {
+		data, err := snapshot.ReadFile(rel)
+		if err != nil {
+			return fmt.Errorf("error leyendo %s del snapshot: %w", rel, err)
+		}
+		contents[rel] = data
+	}
+	if err := takeSnapshot(kind, cliCommand()); err != nil {
+		return err
+	}
+
+	for _, rel := range snapshot.Files {
+		data := contents[rel]
+		path := filepath.Join(configDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("error creando directorio de %s: %w", rel, err)
+		}
+		if err := writeFileAtomic(path, data, 0644); err != nil {
+			return fmt.Errorf("error escribiendo %s: %w", rel, err)
+		}
+	}
+
+	// Los archivos que no existían al tomar el snapshot se eliminan
+	for _, rel := range current {
+		if snapshot.HasFile(rel) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(configDir, filepath.FromSlash(rel))); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error eliminando %s: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// cliCommand es el comando de orgmcron de este proceso (sin el binario), para
+// los snapshots de los cambios hechos desde la CLI
+func cliCommand() string {
+	return strings.Join(os.Args[1:], " ")
+}
+
+// takeSnapshot copia la configuración actual a backups/ antes de modificarla
+// y elimina los snapshots que exceden la retención; command es el comando que
+// hace el cambio. Debe llamarse dentro de withLock, una vez por cambio.
+func takeSnapshot(kind, command string) error {
+	appConfig, err := LoadConfig()
+	if err != nil {
+		// Un config.json inválido no debe impedir guardar el snapshot
+		appConfig = &AppConfig{}
+	}
+	retention := appConfig.BackupRetentionCount()
+	if retention == 0 {
+		return nil
+	}
+
+	files, err := ConfigFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+	backupsDir, err := GetBackupsPath()
+	if err != nil {
+		return err
+	}
+	// Los snapshots pueden contener secretos (env, pingkey)
+	if err := os.MkdirAll(backupsDir, 0700); err != nil {
+		return fmt.Errorf("error creando %s: %w", BackupsDir, err)
+	}
+
+	now := time.Now()
+	snapshot := &Snapshot{
+		ID:      now.Format(snapshotIDLayout),
+		Created: now,
+		Command: command,
+		Kind:    kind,
+		Files:   files,
+	}
+	for i := 1; ; i++ {
+		snapshot.dir = filepath.Join(backupsDir, snapshot.ID)
+		err := os.Mkdir(snapshot.dir, 0700)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("error creando snapshot: %w", err)
+		}
+		snapshot.ID = fmt.Sprintf("%s-%d", now.Format(snapshotIDLayout), i)
+	}
+
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(configDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return fmt.Errorf("error copiando %s al snapshot: %w", rel, err)
+		}
+		path := filepath.Join(snapshot.dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return fmt.Errorf("error creando snapshot: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return fmt.Errorf("error copiando %s al snapshot: %w", rel, err)
+		}
+	}
+
+	// El manifiesto se escribe al final: sin él el snapshot no cuenta
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializando snapshot: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(snapshot.dir, snapshotManifest), data, 0600); err != nil {
+		return fmt.Errorf("error escribiendo snapshot: %w", err)
+	}
+
+	return pruneSnapshots(retention)
+}
+
+// pruneSnapshots elimina los snapshots más antiguos hasta dejar retention
+func pruneSnapshots(retention int) error {
+	snapshots, err := ListSnapshots()
+	if err != nil {
+		return err
+	}
+	for i := retention; i < len(snapshots); i++ {
+		if err := os.RemoveAll(snapshots[i].dir); err != nil {
+			return fmt.Errorf("error eliminando snapshot %s: %w", snapshots[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// readSnapshot lee el manifiesto de un snapshot
+func readSnapshot(dir string) (*Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(dir, snapshotManifest))
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	snapshot.ID = filepath.Base(dir)
+	snapshot.dir = dir
+	return &snapshot, nil
+}
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 const (
@@ -12,21 +15,241 @@ const (
 	JobsFile   = "jobs.json"
 	ConfigFile = "config.json"
 	LogsDir    = "logs"
+	RuntimeDir = "run"
 )
 
 type Job struct {
-	Name           string   `json:"name"`
-	Schedule       string   `json:"schedule"`
-	Commands       []string `json:"commands"`
-	HealthcheckURL string   `json:"healthcheck_url"`
+	Name           string       `json:"name"`
+	Schedule       string       `json:"schedule"`
+	Commands       []Command    `json:"commands"`
+	HealthcheckURL string       `json:"healthcheck_url"`
+	Timeout        string       `json:"timeout,omitempty"`
+	TimeoutGrace   string       `json:"timeout_grace,omitempty"`
+	Retry          *RetryPolicy `json:"retry,omitempty"`
+	OnError        string       `json:"on_error,omitempty"`
+	Cleanup        []Command    `json:"cleanup,omitempty"`
+	ExitCodePolicy string       `json:"exit_code_policy,omitempty"`
+	Concurrency    string       `json:"concurrency,omitempty"`
+	// HealthcheckAttachOutput adjunta el final de la salida de la ejecución a los pings
+	HealthcheckAttachOutput bool `json:"healthcheck_attach_output,omitempty"`
+	// HealthcheckOutputLimit es el máximo de bytes adjuntados (default DefaultOutputLimit)
+	HealthcheckOutputLimit int `json:"healthcheck_output_limit,omitempty"`
+	// Enabled en false pausa el job (omitido = habilitado)
+	Enabled *bool `json:"enabled,omitempty"`
+	// PausedUntil (RFC 3339) reanuda el job automáticamente a esa hora
+	PausedUntil string `json:"paused_until,omitempty"`
+	PauseReason string `json:"pause_reason,omitempty"`
+	// Env y EnvFile (archivos dotenv) definen variables de entorno para los comandos
+	Env     map[string]string `json:"env,omitempty"`
+	EnvFile []string          `json:"env_file,omitempty"`
+	// Workdir es el directorio de trabajo de los comandos (relativo al home si no es absoluto)
+	Workdir string `json:"workdir,omitempty"`
+	// Shell ejecuta los comandos con "<shell> -c" (default sh); "none" los ejecuta sin shell
+	Shell string `json:"shell,omitempty"`
+	// Timezone es la zona horaria del schedule (ej. America/Santo_Domingo); vacío = la global o la local
+	Timezone string `json:"timezone,omitempty"`
+	// Source es el archivo del que se cargó el job (jobs.json o uno de jobs.d)
+	Source string `json:"-"`
 }
 
+// Valores especiales de shell
+const (
+	ShellDefault = "sh"
+	ShellNone    = "none"
+)
+
+// PausedUntilTime retorna la hora de reanudación automática (cero si no tiene)
+func (j Job) PausedUntilTime() (time.Time, error) {
+	if j.PausedUntil == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, j.PausedUntil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("paused_until inválido '%s': %w", j.PausedUntil, err)
+	}
+	return t, nil
+}
+
+// IsPaused indica si el job está pausado en el momento now. Una pausa con
+// paused_until vence sola a esa hora; un paused_until inválido no vence.
+func (j Job) IsPaused(now time.Time) bool {
+	if j.Enabled == nil || *j.Enabled {
+		return false
+	}
+	until, err := j.PausedUntilTime()
+	if err != nil || until.IsZero() {
+		return true
+	}
+	return now.Before(until)
+}
+
+// DefaultOutputLimit es el máximo de bytes de salida adjuntados al healthcheck por defecto
+const DefaultOutputLimit = 10 * 1024
+
+// Valores de concurrency: qué hacer si el job sigue corriendo cuando vuelve a tocar
+const (
+	ConcurrencyAllow   = "allow"
+	ConcurrencySkip    = "skip"
+	ConcurrencyQueue   = "queue"
+	ConcurrencyReplace = "replace"
+)
+
+// Valores de on_error: qué hacer cuando un comando falla
+const (
+	OnErrorContinue          = "continue"
+	OnErrorStop              = "stop"
+	OnErrorStopAndRunCleanup = "stop_and_run_cleanup"
+)
+
+// Valores de exit_code_policy: cómo se calcula el código de salida del job
+const (
+	ExitCodeLast         = "last"
+	ExitCodeFirstFailure = "first-failure"
+	ExitCodeAnyFailure   = "any-failure"
+)
+
+// RetryPolicy define cómo reintentar un job fallido. El delay entre intentos
+// comienza en InitialDelay y se multiplica por Multiplier hasta MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts  int     `json:"max_attempts"`
+	InitialDelay string  `json:"initial_delay,omitempty"`
+	Multiplier   float64 `json:"multiplier,omitempty"`
+	MaxDelay     string  `json:"max_delay,omitempty"`
+	// ExitCodes limita los códigos de salida reintentables; vacío = cualquier fallo
+	ExitCodes []int `json:"exit_codes,omitempty"`
+}
+
+// Command es un comando de un job. En jobs.json puede escribirse como un
+// string simple o como un objeto {"run": "...", "timeout": "10m"} para
+// sobrescribir el timeout del job.
+type Command struct {
+	Run     string `json:"run"`
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// UnmarshalJSON acepta tanto un string como un objeto
+func (c *Command) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '"' {
+		c.Timeout = ""
+		return json.Unmarshal(data, &c.Run)
+	}
+	type plain Command
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+// MarshalJSON escribe el comando como string si no tiene opciones propias
+func (c Command) MarshalJSON() ([]byte, error) {
+	if c.Timeout == "" {
+		return json.Marshal(c.Run)
+	}
+	type plain Command
+	return json.Marshal(plain(c))
+}
+
+// ParseDuration interpreta una duración de configuración; vacío significa 0
+func ParseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duración negativa: %s", s)
+	}
+	return d, nil
+}
+
+// CommandsFromText convierte un texto con un comando por línea en comandos.
+// Las opciones (ej. timeout) de los comandos existentes con el mismo texto se conservan.
+func CommandsFromText(text string, existing []Command) []Command {
+	commands := []Command{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		command := Command{Run: line}
+		for _, e := range existing {
+			if e.Run == line {
+				command = e
+				break
+			}
+		}
+		commands = append(commands, command)
+	}
+	return commands
+}
+
+// CommandsText retorna los comandos como texto, uno por línea
+func CommandsText(commands []Command) string {
+	lines := make([]string, len(commands))
+	for i, c := range commands {
+		lines[i] = c.Run
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SchemaKey es la clave con la que un archivo de jobs indica su JSON Schema
+// a los editores; se acepta en cualquier formato y se conserva al guardar
+const SchemaKey = "$schema"
+
 type JobsConfig struct {
-	Jobs []Job `json:"jobs"`
+	Schema string `json:"$schema,omitempty"`
+	Jobs   []Job  `json:"jobs"`
 }
 
 type AppConfig struct {
+	Schema  string `json:"$schema,omitempty"`
 	PingKey string `json:"pingkey"`
+	// Env y EnvFile son el entorno por defecto de todos los jobs
+	Env     map[string]string `json:"env,omitempty"`
+	EnvFile []string          `json:"env_file,omitempty"`
+	// Timezone es la zona horaria por defecto de los schedules
+	Timezone string `json:"timezone,omitempty"`
+	// BackupRetention es la cantidad de snapshots conservados en backups/
+	// (default DefaultBackupRetention, negativo los desactiva)
+	BackupRetention int `json:"backup_retention,omitempty"`
+	// HistoryMaxSize es el tamaño en MB a partir del cual se rota
+	// history.jsonl (default DefaultHistoryMaxSize, negativo no lo rota)
+	HistoryMaxSize int `json:"history_max_size,omitempty"`
+}
+
+// DefaultHistoryMaxSize es el tamaño (MB) por defecto a partir del cual se
+// rota el historial de ejecuciones
+const DefaultHistoryMaxSize = 5
+
+// BackupRetentionCount retorna cuántos snapshots conservar; 0 si están desactivados
+func (c AppConfig) BackupRetentionCount() int {
+	switch {
+	case c.BackupRetention < 0:
+		return 0
+	case c.BackupRetention == 0:
+		return DefaultBackupRetention
+	}
+	return c.BackupRetention
+}
+
+// HistoryMaxBytes retorna el tamaño a partir del cual se rota el historial;
+// 0 si no se rota
+func (c AppConfig) HistoryMaxBytes() int64 {
+	switch {
+	case c.HistoryMaxSize < 0:
+		return 0
+	case c.HistoryMaxSize == 0:
+		return DefaultHistoryMaxSize << 20
+	}
+	return int64(c.HistoryMaxSize) << 20
+}
+
+// TimezoneFor retorna la zona horaria efectiva de un job: la suya o la global
+func (c AppConfig) TimezoneFor(j Job) string {
+	if j.Timezone != "" {
+		return j.Timezone
+	}
+	return c.Timezone
 }
 
 // GetConfigDir retorna el directorio de configuración completo
@@ -47,6 +270,31 @@ func GetLogsDir() (string, error) {
 	return filepath.Join(configDir, LogsDir), nil
 }
 
+// GetRuntimeDir retorna el directorio de archivos de ejecución del daemon:
+// $XDG_RUNTIME_DIR/orgmcron o, si no está definido, <config>/run
+func GetRuntimeDir() (string, error) {
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		return filepath.Join(runtimeDir, "orgmcron"), nil
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, RuntimeDir), nil
+}
+
+// EnsureRuntimeDir crea el directorio de ejecución si no existe
+func EnsureRuntimeDir() error {
+	runtimeDir, err := GetRuntimeDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(runtimeDir, 0700); err != nil {
+		return fmt.Errorf("error creando directorio de ejecución: %w", err)
+	}
+	return nil
+}
+
 // EnsureConfigDir crea el directorio de configuración si no existe
 func EnsureConfigDir() error {
 	configDir, err := GetConfigDir()
@@ -71,50 +319,87 @@ func EnsureLogsDir() error {
 	return nil
 }
 
-// LoadJobs carga los jobs desde el archivo jobs.json
+// LoadJobs carga los jobs del archivo de jobs (jobs.json, jobs.yaml,
+// jobs.yml o jobs.toml) y de los archivos de jobs.d. Un nombre repetido,
+// en el mismo archivo o en archivos distintos, es un error.
 func LoadJobs() (*JobsConfig, error) {
-	configDir, err := GetConfigDir()
+	files, err := loadJobsFiles()
 	if err != nil {
 		return nil, err
 	}
 
-	jobsPath := filepath.Join(configDir, JobsFile)
-	data, err := os.ReadFile(jobsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// Si no existe, retornar configuración vacía
-			return &JobsConfig{Jobs: []Job{}}, nil
+	config := &JobsConfig{Jobs: []Job{}}
+	seen := make(map[string]string)
+	for _, file := range files {
+		for _, j := range file.jobs {
+			if other, ok := seen[j.Name]; ok {
+				if other == file.path {
+					return nil, fmt.Errorf("job '%s' duplicado en %s", j.Name, DisplayPath(other))
+				}
+				return nil, fmt.Errorf("job '%s' duplicado: definido en %s y en %s", j.Name, DisplayPath(other), DisplayPath(file.path))
+			}
+			seen[j.Name] = file.path
+			config.Jobs = append(config.Jobs, j)
 		}
-		return nil, fmt.Errorf("error leyendo jobs.json: %w", err)
 	}
 
-	var config JobsConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("error parseando jobs.json: %w", err)
-	}
-
-	return &config, nil
+	return config, nil
 }
 
-// SaveJobs guarda los jobs en el archivo jobs.json
+// SaveJobs guarda cada job en el archivo del que se cargó (Source); los
+// nuevos van al archivo de jobs principal, en el formato que ya tenga. Solo
+// se reescriben los archivos que cambian, y cada uno de forma atómica.
 func SaveJobs(config *JobsConfig) error {
+	return withLock(func() error {
+		return saveJobs(config, cliCommand())
+	})
+}
+
+// saveJobs es SaveJobs sin tomar el lock, para usar dentro de withLock;
+// command es el comando registrado en el snapshot
+func saveJobs(config *JobsConfig, command string) error {
 	if err := EnsureConfigDir(); err != nil {
 		return err
 	}
 
-	configDir, err := GetConfigDir()
+	files, err := loadJobsFiles()
 	if err != nil {
 		return err
 	}
+	byPath := make(map[string][]Job)
+	for _, j := range config.Jobs {
+		source := j.Source
+		if source == "" {
+			source = files[0].path
+		}
+		byPath[source] = append(byPath[source], j)
+	}
 
-	jobsPath := filepath.Join(configDir, JobsFile)
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error serializando jobs: %w", err)
+	var changed []*jobsFile
+	for _, file := range files {
+		jobs := byPath[file.path]
+		delete(byPath, file.path)
+		if sameJobs(file.jobs, jobs) {
+			continue
+		}
+		file.jobs = jobs
+		changed = append(changed, file)
+	}
+	// Archivos de origen que ya no están en disco (ej. borrados mientras tanto)
+	for path, jobs := range byPath {
+		changed = append(changed, &jobsFile{path: path, jobs: jobs, single: len(jobs) == 1})
+	}
+	if len(changed) == 0 {
+		return nil
 	}
 
-	if err := os.WriteFile(jobsPath, data, 0644); err != nil {
-		return fmt.Errorf("error escribiendo jobs.json: %w", err)
+	if err := takeSnapshot("", command); err != nil {
+		return fmt.Errorf("error guardando backup: %w", err)
+	}
+	for _, file := range changed {
+		if err := file.save(); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -147,6 +432,28 @@ func LoadConfig() (*AppConfig, error) {
 
 // SaveConfig guarda la configuración de la aplicación
 func SaveConfig(config *AppConfig) error {
+	return withLock(func() error {
+		return saveConfig(config)
+	})
+}
+
+// UpdateConfig carga la configuración, le aplica fn y la guarda, todo bajo el
+// lock de configuración para no perder cambios de otros procesos
+func UpdateConfig(fn func(*AppConfig) error) error {
+	return withLock(func() error {
+		config, err := LoadConfig()
+		if err != nil {
+			return err
+		}
+		if err := fn(config); err != nil {
+			return err
+		}
+		return saveConfig(config)
+	})
+}
+
+// saveConfig es SaveConfig sin tomar el lock, para usar dentro de withLock
+func saveConfig(config *AppConfig) error {
 	if err := EnsureConfigDir(); err != nil {
 		return err
 	}
@@ -161,8 +468,14 @@ func SaveConfig(config *AppConfig) error {
 	if err != nil {
 		return fmt.Errorf("error serializando configuración: %w", err)
 	}
+	if current, err := os.ReadFile(configPath); err == nil && string(current) == string(data) {
+		return nil
+	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	if err := takeSnapshot("", cliCommand()); err != nil {
+		return fmt.Errorf("error guardando backup: %w", err)
+	}
+	if err := writeFileAtomic(configPath, data, 0644); err != nil {
 		return fmt.Errorf("error escribiendo config.json: %w", err)
 	}
 
@@ -187,65 +500,100 @@ func GetJobByName(name string) (*Job, error) {
 
 // AddJob agrega un nuevo job
 func AddJob(job Job) error {
-	config, err := LoadJobs()
-	if err != nil {
-		return err
-	}
+	return withLock(func() error {
+		config, err := LoadJobs()
+		if err != nil {
+			return err
+		}
 
-	// Verificar que no exista un job con el mismo nombre
-	for _, j := range config.Jobs {
-		if j.Name == job.Name {
-			return fmt.Errorf("ya existe un job con el nombre '%s'", job.Name)
+		// Verificar que no exista un job con el mismo nombre
+		for _, j := range config.Jobs {
+			if j.Name == job.Name {
+				return fmt.Errorf("ya existe un job con el nombre '%s'", job.Name)
+			}
 		}
-	}
 
-	config.Jobs = append(config.Jobs, job)
-	return SaveJobs(config)
+		config.Jobs = append(config.Jobs, job)
+		return saveJobs(config, cliCommand())
+	})
 }
 
-// UpdateJob actualiza un job existente
+// UpdateJob reemplaza un job existente
 func UpdateJob(name string, job Job) error {
-	config, err := LoadJobs()
-	if err != nil {
-		return err
-	}
+	return ModifyJob(name, func(j *Job) error {
+		*j = job
+		return nil
+	})
+}
 
-	found := false
-	for i := range config.Jobs {
-		if config.Jobs[i].Name == name {
+// ModifyJob carga un job, le aplica fn y lo guarda, todo bajo el lock de
+// configuración para no perder cambios de otros procesos. El job sigue en el
+// archivo del que se cargó.
+func ModifyJob(name string, fn func(*Job) error) error {
+	return ModifyJobFor(cliCommand(), name, fn)
+}
+
+// ModifyJobFor es ModifyJob con el comando que se registra en el snapshot,
+// para los cambios que no vienen de la línea de comandos de este proceso (ej.
+// una pausa pedida al daemon por su API)
+func ModifyJobFor(command, name string, fn func(*Job) error) error {
+	return withLock(func() error {
+		config, err := LoadJobs()
+		if err != nil {
+			return err
+		}
+
+		for i := range config.Jobs {
+			if config.Jobs[i].Name != name {
+				continue
+			}
+			job := config.Jobs[i]
+			if err := fn(&job); err != nil {
+				return err
+			}
+			job.Source = config.Jobs[i].Source
 			config.Jobs[i] = job
-			found = true
-			break
+			return saveJobs(config, command)
 		}
-	}
 
-	if !found {
 		return fmt.Errorf("job '%s' no encontrado", name)
-	}
-
-	return SaveJobs(config)
+	})
 }
 
 // DeleteJob elimina un job
 func DeleteJob(name string) error {
-	config, err := LoadJobs()
-	if err != nil {
-		return err
-	}
+	return DeleteJobs([]string{name})
+}
 
-	found := false
-	for i, j := range config.Jobs {
-		if j.Name == name {
-			config.Jobs = append(config.Jobs[:i], config.Jobs[i+1:]...)
-			found = true
-			break
+// DeleteJobs elimina varios jobs en un solo cambio (un lock y un snapshot),
+// así 'config undo' los restaura todos juntos. Si alguno no existe no se
+// elimina ninguno.
+func DeleteJobs(names []string) error {
+	return withLock(func() error {
+		config, err := LoadJobs()
+		if err != nil {
+			return err
 		}
-	}
 
-	if !found {
-		return fmt.Errorf("job '%s' no encontrado", name)
-	}
+		remove := make(map[string]bool, len(names))
+		for _, name := range names {
+			remove[name] = true
+		}
+		kept := config.Jobs[:0]
+		for _, j := range config.Jobs {
+			if remove[j.Name] {
+				delete(remove, j.Name)
+				continue
+			}
+			kept = append(kept, j)
+		}
+		for _, name := range names {
+			if remove[name] {
+				return fmt.Errorf("job '%s' no encontrado", name)
+			}
+		}
 
-	return SaveJobs(config)
+		config.Jobs = kept
+		return saveJobs(config, cliCommand())
+	})
 }
-
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MinMaskedLength es el largo mínimo de un valor para enmascararlo en los
+// logs; valores más cortos (ej. "1", "on") aparecerían en cualquier salida
+const MinMaskedLength = 4
+
+// Environment es el entorno con el que se ejecutan los comandos de un job
+type Environment struct {
+	// Vars es el entorno completo en formato KEY=VALUE, listo para exec.Cmd
+	Vars []string
+	// Keys son las variables definidas por la configuración (no heredadas)
+	Keys []string
+	// Secrets son los valores definidos por la configuración, a enmascarar en los logs
+	Secrets []string
+}
+
+// JobEnvironment construye el entorno de un job. Se parte del entorno del
+// proceso y se aplican, en orden: env_file y env globales (config.json) y
+// env_file y env del job. Los valores de env pueden referenciar variables
+// ya definidas con ${VAR} (ver expandEnv).
+func JobEnvironment(job Job) (*Environment, error) {
+	appConfig, err := LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error cargando configuración: %w", err)
+	}
+
+	vars := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			vars[key] = value
+		}
+	}
+
+	defined := make(map[string]bool)
+	layers := []struct {
+		files []string
+		env   map[string]string
+	}{
+		{appConfig.EnvFile, appConfig.Env},
+		{job.EnvFile, job.Env},
+	}
+	for _, layer := range layers {
+		for _, path := range layer.files {
+			fileVars, err := ParseEnvFile(path)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range fileVars {
+				vars[key] = value
+				defined[key] = true
+			}
+		}
+		// Orden estable para que las referencias entre variables sean predecibles
+		keys := make([]string, 0, len(layer.env))
+		for key := range layer.env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			vars[key] = expandEnv(layer.env[key], vars)
+			defined[key] = true
+		}
+	}
+
+	env := &Environment{}
+	for key, value := range vars {
+		env.Vars = append(env.Vars, key+"="+value)
+	}
+	sort.Strings(env.Vars)
+	for key := range defined {
+		env.Keys = append(env.Keys, key)
+		if len(vars[key]) >= MinMaskedLength {
+			env.Secrets = append(env.Secrets, vars[key])
+		}
+	}
+	sort.Strings(env.Keys)
+	return env, nil
+}
+
+// expandEnv reemplaza las referencias ${VAR} por su valor en vars; "$$" es un
+// "$" literal y cualquier otro "$" se deja tal cual, para que valores como
+// contraseñas no se corrompan.
+func expandEnv(value string, vars map[string]string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		if value[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		if value[i+1] == '{' {
+			if end := strings.IndexByte(value[i+2:], '}'); end >= 0 && isEnvName(value[i+2:i+2+end]) {
+				b.WriteString(vars[value[i+2:i+2+end]])
+				i += end + 2
+				continue
+			}
+		}
+		b.WriteByte('$')
+	}
+	return b.String()
+}
+
+// isEnvName indica si name es un nombre de variable válido
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r != '_' && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseEnvFile lee un archivo dotenv: líneas KEY=VALUE, con prefijo "export"
+// opcional, comentarios con # y valores entre comillas simples (literales) o
+// dobles (admiten \n, \t, \" y \\). Las rutas relativas se resuelven desde el
+// directorio de configuración y ~ desde el home.
+func ParseEnvFile(path string) (map[string]string, error) {
+	resolved, err := resolveConfigPath(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo env_file: %w", err)
+	}
+	defer file.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimSpace(strings.TrimPrefix(text, "export "))
+
+		key, raw, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%s:%d: se espera CLAVE=valor", path, line)
+		}
+		value, err := parseEnvValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo env_file %s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// parseEnvValue interpreta el valor de una línea dotenv
+func parseEnvValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	switch raw[0] {
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("comilla simple sin cerrar")
+		}
+		return raw[1 : end+1], nil
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(raw); i++ {
+			c := raw[i]
+			if c == '"' {
+				return b.String(), nil
+			}
+			if c == '\\' && i+1 < len(raw) {
+				i++
+				switch raw[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(raw[i])
+				}
+				continue
+			}
+			b.WriteByte(c)
+		}
+		return "", fmt.Errorf("comilla doble sin cerrar")
+	}
+	// Sin comillas: un " #" inicia un comentario
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = raw[:i]
+	}
+	return strings.TrimSpace(raw), nil
+}
+
+// resolveConfigPath expande ~ y resuelve rutas relativas desde el directorio de configuración
+func resolveConfigPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error obteniendo directorio home: %w", err)
+		}
+		return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+	}
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, path), nil
+}
@@ -0,0 +1,555 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Formatos aceptados para el archivo de jobs
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// JobsFileNames son los nombres aceptados para el archivo de jobs; el formato
+// se detecta por la extensión
+var JobsFileNames = []string{JobsFile, "jobs.yaml", "jobs.yml", "jobs.toml"}
+
+// FormatFromPath detecta el formato de un archivo por su extensión
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	}
+	return "", fmt.Errorf("formato no soportado para %s (usa .json, .yaml, .yml o .toml)", filepath.Base(path))
+}
+
+// GetJobsPath retorna la ruta del archivo de jobs existente. Si no hay
+// ninguno retorna la de jobs.json; si hay más de uno es un error, porque no
+// se sabría cuál usar.
+func GetJobsPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	var found []string
+	for _, name := range JobsFileNames {
+		if _, err := os.Stat(filepath.Join(configDir, name)); err == nil {
+			found = append(found, name)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return filepath.Join(configDir, JobsFile), nil
+	case 1:
+		return filepath.Join(configDir, found[0]), nil
+	}
+	return "", fmt.Errorf("hay más de un archivo de jobs en %s (%s); deja solo uno", configDir, strings.Join(found, ", "))
+}
+
+// decodeJobsFile interpreta un archivo de jobs en el formato indicado. El
+// archivo puede tener una lista "jobs" o definir un solo job (single), y una
+// clave "$schema" para los editores. YAML y TOML se convierten a JSON para
+// que valgan las mismas reglas que en jobs.json (ej. comandos como string u
+// objeto).
+func decodeJobsFile(data []byte, format string) (*jobsFile, error) {
+	data, err := ToJSON(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &jobsFile{jobs: []Job{}}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == "null" {
+		return file, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if raw, ok := fields[SchemaKey]; ok {
+		if err := json.Unmarshal(raw, &file.schema); err != nil {
+			return nil, fmt.Errorf("%s: %w", SchemaKey, err)
+		}
+		delete(fields, SchemaKey)
+	}
+	if _, ok := fields["jobs"]; !ok {
+		if len(fields) == 0 {
+			return file, nil
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			return nil, err
+		}
+		file.jobs, file.single = []Job{job}, true
+		return file, nil
+	}
+
+	var config JobsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if config.Jobs != nil {
+		file.jobs = config.Jobs
+	}
+	return file, nil
+}
+
+// ToJSON convierte el contenido de un archivo YAML o TOML a JSON; el JSON se
+// retorna sin cambios
+func ToJSON(data []byte, format string) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		return json.Marshal(raw)
+	case FormatTOML:
+		var raw map[string]interface{}
+		if _, err := toml.Decode(string(data), &raw); err != nil {
+			return nil, err
+		}
+		return json.Marshal(raw)
+	}
+	return data, nil
+}
+
+// encodeDocument serializa un archivo de jobs (JobsConfig o un Job) en el
+// formato indicado, conservando el orden de los campos de jobs.json. En YAML
+// y TOML los comandos de varias líneas se escriben como bloques de texto. En
+// YAML, previous es el contenido actual del archivo (o nil), del que se
+// conservan los comentarios.
+func encodeDocument(v interface{}, format string, previous []byte) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatJSON {
+		return data, nil
+	}
+
+	root, err := parseOrdered(data)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root.yamlNode()}}
+		var prev yaml.Node
+		if len(previous) > 0 && yaml.Unmarshal(previous, &prev) == nil && prev.Kind == yaml.DocumentNode {
+			keepYAMLComments(doc, &prev)
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatTOML:
+		return root.toml(), nil
+	}
+	return nil, fmt.Errorf("formato no soportado: %s", format)
+}
+
+// orderedNode es un valor JSON que conserva el orden de las claves de los objetos
+type orderedNode struct {
+	// value es el valor de un escalar: string, json.Number, bool o nil
+	value  interface{}
+	keys   []string
+	fields []*orderedNode
+	items  []*orderedNode
+	object bool
+	array  bool
+}
+
+func parseOrdered(data []byte) (*orderedNode, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decodeOrdered(decoder)
+}
+
+func decodeOrdered(decoder *json.Decoder) (*orderedNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		n := &orderedNode{object: true}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			n.keys = append(n.keys, key.(string))
+			n.fields = append(n.fields, value)
+		}
+		_, err := decoder.Token()
+		return n, err
+	case json.Delim('['):
+		n := &orderedNode{array: true}
+		for decoder.More() {
+			item, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+		_, err := decoder.Token()
+		return n, err
+	}
+	return &orderedNode{value: token}, nil
+}
+
+func (n *orderedNode) yamlNode() *yaml.Node {
+	switch {
+	case n.object:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for i, key := range n.keys {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, n.fields[i].yamlNode())
+		}
+		return node
+	case n.array:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range n.items {
+			node.Content = append(node.Content, item.yamlNode())
+		}
+		return node
+	}
+
+	switch v := n.value.(type) {
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+		if strings.Contains(v, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+		return node
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(v)}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+}
+
+// keepYAMLComments copia a node los comentarios de prev, la versión anterior
+// del documento. Las claves se emparejan por nombre, los jobs por su "name" y
+// los demás elementos de una lista por su posición; los comentarios de lo que
+// ya no existe se pierden.
+func keepYAMLComments(node, prev *yaml.Node) {
+	node.HeadComment = prev.HeadComment
+	node.LineComment = prev.LineComment
+	node.FootComment = prev.FootComment
+
+	switch {
+	case node.Kind == yaml.DocumentNode && prev.Kind == yaml.DocumentNode:
+		if len(node.Content) > 0 && len(prev.Content) > 0 {
+			keepYAMLComments(node.Content[0], prev.Content[0])
+		}
+	case node.Kind == yaml.MappingNode && prev.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if j := yamlKeyIndex(prev, node.Content[i].Value); j >= 0 {
+				keepYAMLComments(node.Content[i], prev.Content[j])
+				keepYAMLComments(node.Content[i+1], prev.Content[j+1])
+			}
+		}
+	case node.Kind == yaml.SequenceNode && prev.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			if match := matchingYAMLItem(item, i, prev.Content); match != nil {
+				keepYAMLComments(item, match)
+			}
+		}
+	}
+}
+
+// yamlKeyIndex retorna el índice de la clave key en un mapping (-1 si no está)
+func yamlKeyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchingYAMLItem busca en items el elemento anterior que corresponde a item:
+// el job con el mismo nombre o, si item no es un job, el de la misma posición
+func matchingYAMLItem(item *yaml.Node, index int, items []*yaml.Node) *yaml.Node {
+	if item.Kind == yaml.MappingNode {
+		if i := yamlKeyIndex(item, "name"); i >= 0 {
+			name := item.Content[i+1].Value
+			for _, candidate := range items {
+				if j := yamlKeyIndex(candidate, "name"); candidate.Kind == yaml.MappingNode && j >= 0 && candidate.Content[j+1].Value == name {
+					return candidate
+				}
+			}
+			return nil
+		}
+	}
+	if index < len(items) {
+		return items[index]
+	}
+	return nil
+}
+
+// toml escribe el documento raíz: sus valores simples y luego cada arreglo de
+// objetos como [[tabla]] (ej. [[jobs]])
+func (n *orderedNode) toml() []byte {
+	var buf bytes.Buffer
+	var tables []int
+	for i, field := range n.fields {
+		if field.isTableArray() {
+			tables = append(tables, i)
+		}
+	}
+	n.writeTOMLFields(&buf, tables)
+	for _, i := range tables {
+		for _, item := range n.fields[i].items {
+			if buf.Len() > 0 {
+				buf.WriteString("\n")
+			}
+			fmt.Fprintf(&buf, "[[%s]]\n", tomlKey(n.keys[i]))
+			item.writeTOMLFields(&buf, nil)
+		}
+	}
+	return buf.Bytes()
+}
+
+// writeTOMLFields escribe los campos de un objeto como clave = valor, salvo
+// los indicados en skip y los nulos (TOML no tiene null)
+func (n *orderedNode) writeTOMLFields(w io.Writer, skip []int) {
+	for i, key := range n.keys {
+		if n.fields[i].isNull() || containsInt(skip, i) {
+			continue
+		}
+		fmt.Fprintf(w, "%s = ", tomlKey(key))
+		n.fields[i].writeTOMLValue(w, "")
+		io.WriteString(w, "\n")
+	}
+}
+
+// isTableArray indica si es un arreglo de objetos, que en la raíz se escribe
+// como [[tabla]]
+func (n *orderedNode) isTableArray() bool {
+	if !n.array || len(n.items) == 0 {
+		return false
+	}
+	for _, item := range n.items {
+		if !item.object {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *orderedNode) isNull() bool {
+	return n.value == nil && !n.object && !n.array
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTOMLValue escribe un valor TOML. Los objetos anidados se escriben
+// como tablas en línea y los arreglos largos o con textos de varias líneas,
+// un elemento por línea.
+func (n *orderedNode) writeTOMLValue(w io.Writer, indent string) {
+	switch {
+	case n.object:
+		var parts []string
+		for i, key := range n.keys {
+			if n.fields[i].isNull() {
+				continue
+			}
+			var buf bytes.Buffer
+			n.fields[i].writeTOMLValue(&buf, indent)
+			parts = append(parts, tomlKey(key)+" = "+buf.String())
+		}
+		if len(parts) == 0 {
+			io.WriteString(w, "{}")
+			return
+		}
+		io.WriteString(w, "{ "+strings.Join(parts, ", ")+" }")
+	case n.array:
+		var parts []string
+		multiline := false
+		width := 0
+		for _, item := range n.items {
+			var buf bytes.Buffer
+			item.writeTOMLValue(&buf, indent+"  ")
+			parts = append(parts, buf.String())
+			width += buf.Len()
+			multiline = multiline || strings.Contains(buf.String(), "\n")
+		}
+		if !multiline && width < 60 {
+			io.WriteString(w, "["+strings.Join(parts, ", ")+"]")
+			return
+		}
+		io.WriteString(w, "[\n")
+		for _, part := range parts {
+			io.WriteString(w, indent+"  "+part+",\n")
+		}
+		io.WriteString(w, indent+"]")
+	default:
+		switch v := n.value.(type) {
+		case string:
+			io.WriteString(w, tomlString(v))
+		default:
+			fmt.Fprint(w, v)
+		}
+	}
+}
+
+// tomlKey escribe una clave TOML, entre comillas si no es una clave simple
+func tomlKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return tomlString(key)
+		}
+	}
+	return key
+}
+
+// tomlString escribe un string TOML. Los de varias líneas se escriben como
+// literales multilínea (entre triples comillas simples) cuando es posible.
+func tomlString(s string) string {
+	if strings.Contains(s, "\n") && !strings.Contains(s, "'''") && !hasControlChars(s, "\n\t") {
+		return "'''\n" + s + "'''"
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// hasControlChars indica si s tiene caracteres de control distintos de allowed
+func hasControlChars(s, allowed string) bool {
+	for _, r := range s {
+		if (r < 0x20 || r == 0x7f) && !strings.ContainsRune(allowed, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConvertJobs reescribe el archivo de jobs principal en otro formato (los de
+// jobs.d no se tocan). El archivo original se conserva con la extensión .bak;
+// si ya existe un respaldo así, no se convierte para no sobrescribirlo.
+// Retorna las rutas del archivo original y del nuevo.
+func ConvertJobs(format string) (oldPath, newPath string, err error) {
+	err = withLock(func() error {
+		oldPath, newPath, err = convertJobs(format)
+		return err
+	})
+	return oldPath, newPath, err
+}
+
+func convertJobs(format string) (string, string, error) {
+	oldPath, err := GetJobsPath()
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := os.Stat(oldPath); err != nil {
+		return "", "", fmt.Errorf("no hay archivo de jobs para convertir: %w", err)
+	}
+	oldFormat, err := FormatFromPath(oldPath)
+	if err != nil {
+		return "", "", err
+	}
+	if oldFormat == format {
+		return "", "", fmt.Errorf("%s ya está en formato %s", filepath.Base(oldPath), format)
+	}
+	backupPath := oldPath + ".bak"
+	if _, err := os.Lstat(backupPath); err == nil {
+		return "", "", fmt.Errorf("ya existe %s de una conversión anterior; muévelo o elimínalo antes de convertir", backupPath)
+	} else if !os.IsNotExist(err) {
+		return "", "", fmt.Errorf("error verificando %s: %w", backupPath, err)
+	}
+
+	file, err := readJobsFile(oldPath)
+	if err != nil {
+		return "", "", err
+	}
+	data, err := encodeDocument(&JobsConfig{Schema: file.schema, Jobs: file.jobs}, format, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("error serializando jobs: %w", err)
+	}
+
+	// El archivo nuevo debe describir exactamente los mismos jobs
+	converted, err := decodeJobsFile(data, format)
+	if err != nil {
+		return "", "", fmt.Errorf("error verificando la conversión: %w", err)
+	}
+	if !sameJobs(file.jobs, converted.jobs) {
+		return "", "", fmt.Errorf("la conversión a %s no conserva todos los datos", format)
+	}
+
+	if err := takeSnapshot("", cliCommand()); err != nil {
+		return "", "", fmt.Errorf("error guardando backup: %w", err)
+	}
+	newPath := filepath.Join(filepath.Dir(oldPath), "jobs."+format)
+	if err := writeFileAtomic(newPath, data, 0644); err != nil {
+		return "", "", fmt.Errorf("error escribiendo %s: %w", filepath.Base(newPath), err)
+	}
+	if err := os.Rename(oldPath, backupPath); err != nil {
+		os.Remove(newPath)
+		return "", "", fmt.Errorf("error respaldando %s: %w", filepath.Base(oldPath), err)
+	}
+	return oldPath, newPath, nil
+}
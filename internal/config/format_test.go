@@ -0,0 +1,141 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+// roundTripJobs cubre los valores que el codificador TOML propio y el de YAML
+// deben escapar o escribir en bloque
+var roundTripJobs = []Job{
+	{
+		Name:     "backup",
+		Schedule: "CRON_TZ=America/Santo_Domingo 0 2 * * *",
+		Commands: []Command{
+			{Run: "rsync -a /a /b"},
+			{Run: "pg_dump db > /tmp/db.sql", Timeout: "1h"},
+			{Run: "echo \"uno\"\necho 'dos'\n"},
+		},
+		HealthcheckURL: "https://hc.or-gm.com/ping/{pingkey}/backup",
+		Timeout:        "2h",
+		Retry:          &RetryPolicy{MaxAttempts: 3, InitialDelay: "30s", Multiplier: 2.5, ExitCodes: []int{1, 75}},
+		OnError:        OnErrorStopAndRunCleanup,
+		Cleanup:        []Command{{Run: "rm -f /tmp/db.sql"}},
+		Enabled:        boolPtr(false),
+		PauseReason:    "tabs\ty \\ barras # no es comentario",
+		Env:            map[string]string{"PATH": "${HOME}/bin:${PATH}", "with space": "x=y", "ñ": "\x01"},
+		EnvFile:        []string{"~/.config/restic/credentials.env"},
+	},
+	{
+		Name:     "limpia",
+		Schedule: "@every 1h",
+		Commands: []Command{{Run: "true"}},
+	},
+}
+
+func TestEncodeDocumentRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatYAML, FormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			data, err := encodeDocument(&JobsConfig{Schema: "jobs.schema.json", Jobs: roundTripJobs}, format, nil)
+			if err != nil {
+				t.Fatalf("encodeDocument: %v", err)
+			}
+			file, err := decodeJobsFile(data, format)
+			if err != nil {
+				t.Fatalf("decodeJobsFile: %v\n%s", err, data)
+			}
+			if file.schema != "jobs.schema.json" {
+				t.Errorf("$schema = %q", file.schema)
+			}
+			if !reflect.DeepEqual(file.jobs, roundTripJobs) {
+				t.Errorf("jobs distintos tras ida y vuelta:\n%s\ngot:  %+v\nwant: %+v", data, file.jobs, roundTripJobs)
+			}
+		})
+	}
+}
+
+func TestEncodeDocumentSingleJob(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatYAML, FormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			data, err := encodeDocument(singleJobDocument{Job: roundTripJobs[1]}, format, nil)
+			if err != nil {
+				t.Fatalf("encodeDocument: %v", err)
+			}
+			file, err := decodeJobsFile(data, format)
+			if err != nil {
+				t.Fatalf("decodeJobsFile: %v\n%s", err, data)
+			}
+			if !file.single || len(file.jobs) != 1 || !reflect.DeepEqual(file.jobs[0], roundTripJobs[1]) {
+				t.Errorf("single = %v, jobs = %+v\n%s", file.single, file.jobs, data)
+			}
+		})
+	}
+}
+
+func TestEncodeDocumentKeepsYAMLComments(t *testing.T) {
+	previous := []byte(`# yaml-language-server: $schema=jobs.schema.json
+
+jobs:
+  # Limpieza
+  - name: limpia
+    schedule: "@every 1h" # cada hora
+    commands: ["true"]
+  # Respaldo nocturno
+  - name: backup
+    schedule: "@daily"
+    commands: ["rsync -a /a /b"]
+`)
+	// El orden cambia y "backup" se elimina: los comentarios siguen a su job
+	jobs := []Job{
+		{Name: "nuevo", Schedule: "@daily", Commands: []Command{{Run: "true"}}},
+		{Name: "limpia", Schedule: "@every 2h", Commands: []Command{{Run: "true"}}},
+	}
+	data, err := encodeDocument(&JobsConfig{Jobs: jobs}, FormatYAML, previous)
+	if err != nil {
+		t.Fatalf("encodeDocument: %v", err)
+	}
+	out := string(data)
+
+	for _, want := range []string{"# yaml-language-server: $schema=jobs.schema.json", "# Limpieza\n  - name: limpia", "schedule: '@every 2h' # cada hora"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("falta %q en:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Respaldo nocturno") {
+		t.Errorf("se conservó el comentario de un job eliminado:\n%s", out)
+	}
+}
+
+func TestTOMLString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"simple", `"simple"`},
+		{`con "comillas" y \`, `"con \"comillas\" y \\"`},
+		{"tab\tcontrol\x01", `"tab\tcontrol\u0001"`},
+	}
+	for _, tt := range tests {
+		if got := tomlString(tt.in); got != tt.want {
+			t.Errorf("tomlString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTOMLKey(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"name", "name"},
+		{"max_attempts", "max_attempts"},
+		{"$schema", `"$schema"`},
+		{"with space", `"with space"`},
+	}
+	for _, tt := range tests {
+		if got := tomlKey(tt.in); got != tt.want {
+			t.Errorf("tomlKey(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
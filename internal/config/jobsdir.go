@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// JobsDir es el directorio de archivos de jobs adicionales (drop-ins). Cada
+// archivo define un job o una lista "jobs", así cada persona o herramienta de
+// gestión de configuración puede manejar sus propios archivos.
+const JobsDir = "jobs.d"
+
+// jobsFile es el contenido de un archivo de jobs
+type jobsFile struct {
+	path string
+	jobs []Job
+	// single indica que el archivo define un solo job, sin la lista "jobs"
+	single bool
+	// schema es el valor de "$schema", que se conserva al reescribir el archivo
+	schema string
+	dropIn bool
+}
+
+// singleJobDocument es un archivo que define un solo job
+type singleJobDocument struct {
+	Schema string `json:"$schema,omitempty"`
+	Job
+}
+
+// GetJobsDirPath retorna la ruta del directorio jobs.d
+func GetJobsDirPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, JobsDir), nil
+}
+
+// IsJobsDropIn indica si un archivo de jobs.d se carga: por su extensión y
+// sin contar los ocultos (ej. temporales de editores)
+func IsJobsDropIn(name string) bool {
+	if strings.HasPrefix(filepath.Base(name), ".") {
+		return false
+	}
+	_, err := FormatFromPath(name)
+	return err == nil
+}
+
+// DisplayPath muestra la ruta de un archivo de configuración relativa al
+// directorio de configuración (ej. jobs.d/backup.yaml)
+func DisplayPath(path string) string {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(configDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// loadJobsFiles lee el archivo de jobs principal y los de jobs.d, en orden alfabético
+func loadJobsFiles() ([]*jobsFile, error) {
+	mainPath, err := GetJobsPath()
+	if err != nil {
+		return nil, err
+	}
+	mainFile, err := readJobsFile(mainPath)
+	if err != nil {
+		return nil, err
+	}
+	files := []*jobsFile{mainFile}
+
+	jobsDir, err := GetJobsDirPath()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(jobsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error leyendo %s: %w", JobsDir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !IsJobsDropIn(entry.Name()) {
+			continue
+		}
+		file, err := readJobsFile(filepath.Join(jobsDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		file.dropIn = true
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// readJobsFile lee un archivo de jobs; si no existe retorna uno vacío
+func readJobsFile(path string) (*jobsFile, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &jobsFile{path: path, jobs: []Job{}}, nil
+		}
+		return nil, fmt.Errorf("error leyendo %s: %w", DisplayPath(path), err)
+	}
+
+	file, err := decodeJobsFile(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("error parseando %s: %w", DisplayPath(path), err)
+	}
+	file.path = path
+	for i := range file.jobs {
+		file.jobs[i].Source = path
+	}
+	return file, nil
+}
+
+// save escribe el archivo en su formato, conservando su forma (un job o una
+// lista). Un archivo de jobs.d que se queda sin jobs se elimina.
+func (f *jobsFile) save() error {
+	if f.dropIn && len(f.jobs) == 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error eliminando %s: %w", DisplayPath(f.path), err)
+		}
+		return nil
+	}
+
+	format, err := FormatFromPath(f.path)
+	if err != nil {
+		return err
+	}
+	if f.jobs == nil {
+		f.jobs = []Job{}
+	}
+	// En YAML se conservan los comentarios del archivo actual
+	var previous []byte
+	if format == FormatYAML {
+		previous, _ = os.ReadFile(f.path)
+	}
+	var data []byte
+	if f.single && len(f.jobs) == 1 {
+		data, err = encodeDocument(singleJobDocument{Schema: f.schema, Job: f.jobs[0]}, format, previous)
+	} else {
+		data, err = encodeDocument(&JobsConfig{Schema: f.schema, Jobs: f.jobs}, format, previous)
+	}
+	if err != nil {
+		return fmt.Errorf("error serializando jobs: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("error creando directorio de %s: %w", DisplayPath(f.path), err)
+	}
+	if err := writeFileAtomic(f.path, data, 0644); err != nil {
+		return fmt.Errorf("error escribiendo %s: %w", DisplayPath(f.path), err)
+	}
+	return nil
+}
+
+// sameJobs indica si dos listas de jobs tienen el mismo contenido
+func sameJobs(a, b []Job) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	if len(a) == 0 {
+		return true
+	}
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(dataA) == string(dataB)
+}
@@ -0,0 +1,169 @@
+// Package diff compara textos línea a línea en formato unificado
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// op es una línea del resultado: ' ' igual, '-' eliminada, '+' agregada
+type op struct {
+	kind byte
+	line string
+}
+
+// Unified retorna las diferencias entre a y b en formato unificado (como
+// `diff -u`), con context líneas alrededor de cada cambio. Vacío si son iguales.
+func Unified(a, b, nameA, nameB string, context int) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	changed := false
+	for _, o := range ops {
+		if o.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	// Líneas de a y de b anteriores a cada op, para los encabezados @@
+	posA := make([]int, len(ops)+1)
+	posB := make([]int, len(ops)+1)
+	for i, o := range ops {
+		posA[i+1], posB[i+1] = posA[i], posB[i]
+		if o.kind != '+' {
+			posA[i+1]++
+		}
+		if o.kind != '-' {
+			posB[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+	i := 0
+	for i < len(ops) {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// Extender el bloque mientras el siguiente cambio quede cerca
+		end := i
+		for {
+			for end < len(ops) && ops[end].kind != ' ' {
+				end++
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next < len(ops) && next-end <= 2*context {
+				end = next
+				continue
+			}
+			end += context
+			if end > len(ops) {
+				end = len(ops)
+			}
+			break
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(posA[start], posA[end]), hunkRange(posB[start], posB[end]))
+		for _, o := range ops[start:end] {
+			fmt.Fprintf(&out, "%c%s\n", o.kind, o.line)
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// hunkRange formatea el rango de un bloque como lo hace diff -u
+func hunkRange(from, to int) string {
+	count := to - from
+	if count == 0 {
+		return fmt.Sprintf("%d,0", from)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", from+1)
+	}
+	return fmt.Sprintf("%d,%d", from+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines calcula las operaciones que transforman a en b con la subsecuencia
+// común más larga. Los extremos iguales se descartan antes, así un cambio
+// pequeño en un archivo grande usa una tabla pequeña.
+func diffLines(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []op
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{' ', line})
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+	n, m := len(midA), len(midB)
+	// lcs[i*(m+1)+j] es la longitud de la LCS de midA[i:] y midB[j:]
+	lcs := make([]int32, (n+1)*(m+1))
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j+1] + 1
+			} else if lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1] {
+				lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j]
+			} else {
+				lcs[i*(m+1)+j] = lcs[i*(m+1)+j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case midA[i] == midB[j]:
+			ops = append(ops, op{' ', midA[i]})
+			i++
+			j++
+		case lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]:
+			ops = append(ops, op{'-', midA[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', midB[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, op{'-', midA[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, op{'+', midB[j]})
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{' ', line})
+	}
+	return ops
+}
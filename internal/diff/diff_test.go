@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func lines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		b.WriteString(strings.Repeat("x", i) + "\n")
+	}
+	return b.String()
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{
+			name: "iguales",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			want: "",
+		},
+		{
+			name:    "cambio en medio",
+			a:       "a\nb\nc\n",
+			b:       "a\nB\nc\n",
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:    "archivo nuevo",
+			a:       "",
+			b:       "uno\ndos\n",
+			context: 3,
+			want:    "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+uno\n+dos\n",
+		},
+		{
+			name:    "archivo eliminado",
+			a:       "uno\n",
+			b:       "",
+			context: 3,
+			want:    "--- a\n+++ b\n@@ -1 +0,0 @@\n-uno\n",
+		},
+		{
+			name:    "cambios lejanos en bloques separados",
+			a:       lines(10),
+			b:       strings.Replace(strings.Replace(lines(10), "x\n", "A\n", 1), "xxxxxxxxxx\n", "B\n", 1),
+			context: 1,
+			want: "--- a\n+++ b\n" +
+				"@@ -1,2 +1,2 @@\n-x\n+A\n xx\n" +
+				"@@ -9,2 +9,2 @@\n xxxxxxxxx\n-xxxxxxxxxx\n+B\n",
+		},
+		{
+			name:    "cambios cercanos en un solo bloque",
+			a:       "a\nb\nc\nd\n",
+			b:       "A\nb\nc\nD\n",
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n-d\n+D\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified(tt.a, tt.b, "a", "b", tt.context); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package healthcheck
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"strings"
@@ -9,17 +10,30 @@ import (
 	"github.com/osmargm1202/orgmcron/internal/logger"
 )
 
-// SendHealthcheck envía un request GET al healthcheck URL
-func SendHealthcheck(url string, pingKey string) error {
+// Señales del protocolo de healthchecks.io, se agregan como sufijo a la URL
+const (
+	SignalStart = "start"
+	SignalFail  = "fail"
+)
+
+// SendHealthcheck envía un ping al healthcheck URL. Si body no está vacío se
+// envía como POST (healthchecks.io lo guarda junto al ping); si no, como GET.
+func SendHealthcheck(url string, pingKey string, body []byte) error {
 	// Reemplazar {pingkey} en la URL
 	finalURL := strings.ReplaceAll(url, "{pingkey}", pingKey)
-	logger.DebugLog("Enviando healthcheck a URL: %s", finalURL)
+	logger.DebugLog("Enviando healthcheck a URL: %s (%d bytes adjuntos)", finalURL, len(body))
 
 	client := &http.Client{
 		Timeout: 10 * time.Second,
 	}
 
-	resp, err := client.Get(finalURL)
+	var resp *http.Response
+	var err error
+	if len(body) > 0 {
+		resp, err = client.Post(finalURL, "text/plain; charset=utf-8", bytes.NewReader(body))
+	} else {
+		resp, err = client.Get(finalURL)
+	}
 	if err != nil {
 		logger.DebugLog("Error enviando healthcheck a %s: %v", finalURL, err)
 		return fmt.Errorf("error enviando healthcheck: %w", err)
@@ -37,3 +51,28 @@ func SendHealthcheck(url string, pingKey string) error {
 	return nil
 }
 
+// SendStart notifica el inicio de una ejecución (<url>/start)
+func SendStart(url string, pingKey string) error {
+	return SendHealthcheck(signalURL(url, SignalStart), pingKey, nil)
+}
+
+// SendFailure notifica un fallo. Si el código de salida es válido (1-255) se
+// usa <url>/<código> para que quede registrado; si no, <url>/fail. body es
+// opcional (ej. el final de la salida del job).
+func SendFailure(url string, pingKey string, exitCode int, body []byte) error {
+	signal := SignalFail
+	if exitCode > 0 && exitCode <= 255 {
+		signal = fmt.Sprintf("%d", exitCode)
+	}
+	return SendHealthcheck(signalURL(url, signal), pingKey, body)
+}
+
+// signalURL agrega una señal a la URL del healthcheck respetando el query string
+func signalURL(url string, signal string) string {
+	base, query, hasQuery := strings.Cut(url, "?")
+	result := strings.TrimRight(base, "/") + "/" + signal
+	if hasQuery {
+		result += "?" + query
+	}
+	return result
+}
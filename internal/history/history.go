@@ -0,0 +1,232 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/logger"
+)
+
+// HistoryFile es el archivo (JSON por línea) con el historial de ejecuciones
+const HistoryFile = "history.jsonl"
+
+// RotatedSuffix es el sufijo del historial rotado (history.jsonl.1). Se
+// conserva una sola generación, así el historial ocupa como mucho el doble de
+// history_max_size.
+const RotatedSuffix = ".1"
+
+// Origen de una ejecución
+const (
+	TriggerSchedule = "schedule" // programada por el daemon
+	TriggerManual   = "manual"   // orgmcron run
+	TriggerDaemon   = "daemon"   // orgmcron run --daemon
+)
+
+// Resultado del ping final al healthcheck
+const (
+	HealthcheckOK      = "ok"
+	HealthcheckError   = "error"
+	HealthcheckSkipped = "skipped"
+)
+
+// Record es una ejecución registrada en el historial
+type Record struct {
+	Job         string    `json:"job"`
+	Trigger     string    `json:"trigger"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Duration    float64   `json:"duration_seconds"`
+	ExitCode    int       `json:"exit_code"`
+	Status      string    `json:"status"`
+	Attempt     int       `json:"attempt"`
+	MaxAttempts int       `json:"max_attempts"`
+	// Healthcheck es el resultado del ping final: ok, error o skipped (vacío si el job no tiene healthcheck)
+	Healthcheck      string `json:"healthcheck,omitempty"`
+	HealthcheckError string `json:"healthcheck_error,omitempty"`
+	// Error describe por qué el job no pudo ejecutarse
+	Error string `json:"error,omitempty"`
+}
+
+// Filter selecciona registros del historial
+type Filter struct {
+	Job    string
+	Status string
+	Since  time.Time
+	// Limit es el máximo de registros (los más recientes); 0 = sin límite
+	Limit int
+}
+
+// Match indica si un registro cumple el filtro
+func (f Filter) Match(r Record) bool {
+	if f.Job != "" && r.Job != f.Job {
+		return false
+	}
+	if f.Status != "" && r.Status != f.Status {
+		return false
+	}
+	if !f.Since.IsZero() && r.Start.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// Las escrituras del mismo proceso se serializan; entre procesos cada
+// registro se escribe con una sola llamada en modo append
+var writeMu sync.Mutex
+
+// GetHistoryPath retorna la ruta del archivo de historial
+func GetHistoryPath() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, HistoryFile), nil
+}
+
+// Append agrega un registro al historial. Si el archivo supera
+// history_max_size se rota a history.jsonl.1, que reemplaza al anterior.
+func Append(r Record) error {
+	if err := config.EnsureConfigDir(); err != nil {
+		return err
+	}
+	historyPath, err := GetHistoryPath()
+	if err != nil {
+		return err
+	}
+	var maxBytes int64
+	if appConfig, err := config.LoadConfig(); err == nil {
+		maxBytes = appConfig.HistoryMaxBytes()
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("error serializando registro de historial: %w", err)
+	}
+	data = append(data, '\n')
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	file, err := openLocked(historyPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("error escribiendo historial: %w", err)
+	}
+
+	if maxBytes > 0 {
+		if info, err := file.Stat(); err == nil && info.Size() >= maxBytes {
+			if err := os.Rename(historyPath, historyPath+RotatedSuffix); err != nil {
+				return fmt.Errorf("error rotando historial: %w", err)
+			}
+			logger.DebugLog("Historial rotado: %d bytes", info.Size())
+		}
+	}
+	return nil
+}
+
+// openLocked abre el historial para agregar con un flock exclusivo, que
+// serializa la escritura y la rotación entre procesos. Si otro proceso lo rotó
+// mientras se esperaba el lock, se abre el archivo nuevo.
+func openLocked(historyPath string) (*os.File, error) {
+	for {
+		file, err := os.OpenFile(historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("error abriendo historial: %w", err)
+		}
+		if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("error tomando lock del historial: %w", err)
+		}
+		opened, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("error abriendo historial: %w", err)
+		}
+		if current, err := os.Stat(historyPath); err == nil && os.SameFile(opened, current) {
+			// Cerrar el archivo libera el lock
+			return file, nil
+		}
+		file.Close()
+	}
+}
+
+// Load lee los registros del historial que cumplen el filtro (incluido el
+// archivo rotado), del más antiguo al más reciente. Las líneas dañadas se
+// ignoran.
+func Load(filter Filter) ([]Record, error) {
+	historyPath, err := GetHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []Record{}
+	for _, path := range []string{historyPath + RotatedSuffix, historyPath} {
+		if records, err = loadFile(path, filter, records); err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[len(records)-filter.Limit:]
+	}
+	return records, nil
+}
+
+// loadFile agrega a records los registros de un archivo de historial que
+// cumplen el filtro; un archivo que no existe no tiene registros
+func loadFile(path string, filter Filter, records []Record) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, fmt.Errorf("error abriendo historial: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			logger.DebugLog("Línea %d de %s inválida, se ignora: %v", line, filepath.Base(path), err)
+			continue
+		}
+		if filter.Match(r) {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo historial: %w", err)
+	}
+	return records, nil
+}
+
+// LastByJob retorna la ejecución más reciente de cada job
+func LastByJob() (map[string]Record, error) {
+	records, err := Load(Filter{})
+	if err != nil {
+		return nil, err
+	}
+	last := make(map[string]Record)
+	for _, r := range records {
+		last[r.Job] = r
+	}
+	return last, nil
+}
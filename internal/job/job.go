@@ -1,80 +1,373 @@
 package job
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/osmargm1202/orgmcron/internal/config"
 	"github.com/osmargm1202/orgmcron/internal/logger"
 )
 
-// Execute ejecuta un job y retorna el código de salida
-func Execute(job config.Job, pingKey string) (int, error) {
+const (
+	// ExitCodeTimeout es el código reportado cuando un comando excede su timeout (igual que timeout(1))
+	ExitCodeTimeout = 124
+	// ExitCodeCanceled es el código reportado cuando la ejecución se cancela (128 + SIGTERM)
+	ExitCodeCanceled = 143
+	// DefaultTimeoutGrace es el tiempo entre SIGTERM y SIGKILL si el job no define timeout_grace
+	DefaultTimeoutGrace = 10 * time.Second
+	// ArchiveDir es el subdirectorio de logs donde se archivan los logs de jobs eliminados
+	ArchiveDir = "archive"
+	// outputWaitDelay es cuánto se espera la salida de procesos en segundo plano tras terminar un comando
+	outputWaitDelay = 2 * time.Second
+)
+
+// Estados posibles de una ejecución
+const (
+	StatusSuccess  = "success"
+	StatusFailed   = "failed"
+	StatusTimeout  = "timeout"
+	StatusCanceled = "canceled"
+)
+
+var (
+	errTimeout  = errors.New("timeout excedido")
+	errCanceled = errors.New("ejecución cancelada")
+)
+
+// Options controla una ejecución concreta de un job
+type Options struct {
+	// Context permite cancelar la ejecución (ej. concurrency: replace); nil = sin cancelación
+	Context context.Context
+	// Attempt y MaxAttempts identifican el intento dentro de una política de reintentos
+	Attempt     int
+	MaxAttempts int
+	// OutputLimit > 0 conserva los últimos OutputLimit bytes de la salida en Result.Output
+	OutputLimit int
+	// Stream recibe una copia de todo lo escrito en el log (ej. orgmcron run --stream)
+	Stream io.Writer
+}
+
+// Result contiene el resultado de la ejecución de un job
+type Result struct {
+	ExitCode int
+	Status   string
+	Attempt  int
+	// Output contiene el final de la salida de esta ejecución si se pidió con OutputLimit
+	Output []byte
+}
+
+// Execute ejecuta un job y retorna su resultado
+func Execute(job config.Job, opts Options) (*Result, error) {
 	logger.DebugLog("Iniciando ejecución del job: %s", job.Name)
-	
+
 	logsDir, err := config.GetLogsDir()
 	if err != nil {
 		logger.DebugLog("Error obteniendo directorio de logs para job '%s': %v", job.Name, err)
-		return 1, fmt.Errorf("error obteniendo directorio de logs: %w", err)
+		return nil, fmt.Errorf("error obteniendo directorio de logs: %w", err)
 	}
 
 	if err := config.EnsureLogsDir(); err != nil {
 		logger.DebugLog("Error creando directorio de logs para job '%s': %v", job.Name, err)
-		return 1, err
+		return nil, err
+	}
+
+	grace, err := config.ParseDuration(job.TimeoutGrace)
+	if err != nil {
+		return nil, fmt.Errorf("timeout_grace inválido: %w", err)
+	}
+	if grace == 0 {
+		grace = DefaultTimeoutGrace
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	switch job.OnError {
+	case "", config.OnErrorContinue, config.OnErrorStop, config.OnErrorStopAndRunCleanup:
+	default:
+		return nil, fmt.Errorf("on_error inválido: %s", job.OnError)
+	}
+	switch job.ExitCodePolicy {
+	case "", config.ExitCodeLast, config.ExitCodeFirstFailure, config.ExitCodeAnyFailure:
+	default:
+		return nil, fmt.Errorf("exit_code_policy inválido: %s", job.ExitCodePolicy)
 	}
 
 	logFile := filepath.Join(logsDir, job.Name+".log")
 	logger.DebugLog("Escribiendo logs del job '%s' a: %s", job.Name, logFile)
-	
+
 	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		logger.DebugLog("Error abriendo archivo de log para job '%s': %v", job.Name, err)
-		return 1, fmt.Errorf("error abriendo archivo de log: %w", err)
+		return nil, fmt.Errorf("error abriendo archivo de log: %w", err)
 	}
 	defer file.Close()
 
+	// Todo lo que se escribe en el log pasa por out; si se pidió, también se
+	// conserva el final de la salida para el healthcheck y se copia a Stream
+	writers := []io.Writer{file}
+	var tail *tailBuffer
+	if opts.OutputLimit > 0 {
+		tail = &tailBuffer{limit: opts.OutputLimit}
+		writers = append(writers, tail)
+	}
+	if opts.Stream != nil {
+		writers = append(writers, opts.Stream)
+	}
+	var out io.Writer = file
+	if len(writers) > 1 {
+		out = io.MultiWriter(writers...)
+	}
+
+	// Identificar el intento cuando hay reintentos configurados
+	attemptLabel := ""
+	if opts.MaxAttempts > 1 {
+		attemptLabel = fmt.Sprintf(" [intento %d/%d]", opts.Attempt, opts.MaxAttempts)
+	}
+
 	// Escribir timestamp de inicio
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	file.WriteString(fmt.Sprintf("\n=== Ejecución iniciada: %s ===\n", timestamp))
-	logger.DebugLog("Job '%s': ejecutando %d comandos", job.Name, len(job.Commands))
+	fmt.Fprintf(out, "\n=== Ejecución iniciada: %s%s ===\n", timestamp, attemptLabel)
+	logger.DebugLog("Job '%s': ejecutando %d comandos%s", job.Name, len(job.Commands), attemptLabel)
+
+	onError := job.OnError
+	if onError == "" {
+		onError = config.OnErrorContinue
+	}
+	policy := job.ExitCodePolicy
+	if policy == "" {
+		policy = config.ExitCodeLast
+	}
+
+	// Entorno de los comandos; sus valores se enmascaran en toda la salida
+	var last, firstFailure, lastFailure, timedOut *stepResult
+	commands := job.Commands
+	env, err := config.JobEnvironment(job)
+	if err != nil {
+		logger.DebugLog("Job '%s': entorno inválido: %v", job.Name, err)
+		fmt.Fprintf(out, "\n[ERROR] Entorno inválido: %v\n", err)
+		last = &stepResult{ExitCode: 1, Status: StatusFailed}
+		firstFailure, lastFailure = last, last
+		commands = nil
+		env = &config.Environment{}
+	} else if len(env.Keys) > 0 {
+		masked := make([]string, len(env.Keys))
+		for i, key := range env.Keys {
+			masked[i] = key + "=" + maskedValue
+		}
+		fmt.Fprintf(out, "\n[Entorno] %s\n", strings.Join(masked, ", "))
+	}
+	maskedOut := newMaskWriter(out, env.Secrets)
+	if len(env.Secrets) > 0 {
+		out = maskedOut
+	}
 
 	// Ejecutar comandos en orden
-	var lastExitCode int
-	for i, cmdStr := range job.Commands {
-		logger.DebugLog("Job '%s': ejecutando comando %d/%d: %s", job.Name, i+1, len(job.Commands), cmdStr)
-		file.WriteString(fmt.Sprintf("\n[Comando %d/%d] %s\n", i+1, len(job.Commands), cmdStr))
-
-		cmd := exec.Command("sh", "-c", cmdStr)
-		cmd.Stdout = file
-		cmd.Stderr = file
-
-		if err := cmd.Run(); err != nil {
-			if exitError, ok := err.(*exec.ExitError); ok {
-				lastExitCode = exitError.ExitCode()
-				logger.DebugLog("Job '%s': comando %d falló con código de salida: %d", job.Name, i+1, lastExitCode)
-				file.WriteString(fmt.Sprintf("\n[ERROR] Comando falló con código de salida: %d\n", lastExitCode))
-			} else {
-				lastExitCode = 1
-				logger.DebugLog("Job '%s': error ejecutando comando %d: %v", job.Name, i+1, err)
-				file.WriteString(fmt.Sprintf("\n[ERROR] Error ejecutando comando: %v\n", err))
-			}
-			// Continuar con el siguiente comando aunque este haya fallado
-		} else {
-			lastExitCode = 0
-			logger.DebugLog("Job '%s': comando %d completado exitosamente", job.Name, i+1)
-			file.WriteString(fmt.Sprintf("\n[OK] Comando completado exitosamente\n"))
+	stopped := false
+	for i, c := range commands {
+		step := runStep(ctx, out, job, fmt.Sprintf("Comando %d/%d", i+1, len(job.Commands)), c, grace, env.Vars)
+		last = &step
+		if step.Status == StatusSuccess {
+			continue
+		}
+		if step.Status == StatusCanceled {
+			// Una ejecución cancelada no continúa ni ejecuta limpieza
+			firstFailure, lastFailure = &step, &step
+			break
+		}
+
+		if firstFailure == nil {
+			firstFailure = &step
+		}
+		lastFailure = &step
+		if step.Status == StatusTimeout && timedOut == nil {
+			timedOut = &step
+		}
+
+		if onError != config.OnErrorContinue {
+			stopped = true
+			logger.DebugLog("Job '%s': deteniendo tras el fallo del comando %d (on_error: %s)", job.Name, i+1, onError)
+			fmt.Fprintf(out, "\n[STOP] Job detenido tras el fallo del comando %d/%d\n", i+1, len(job.Commands))
+			break
+		}
+		// Con on_error=continue se sigue con el siguiente comando aunque este haya fallado
+	}
+
+	// Comandos de limpieza: su resultado no afecta al código del job
+	if stopped && onError == config.OnErrorStopAndRunCleanup {
+		for i, c := range job.Cleanup {
+			runStep(ctx, out, job, fmt.Sprintf("Limpieza %d/%d", i+1, len(job.Cleanup)), c, grace, env.Vars)
 		}
 	}
 
+	result := &Result{Status: StatusSuccess, Attempt: opts.Attempt}
+	defer func() {
+		if tail != nil {
+			result.Output = tail.Bytes()
+		}
+	}()
+	var reported *stepResult
+	switch policy {
+	case config.ExitCodeFirstFailure:
+		reported = firstFailure
+	case config.ExitCodeAnyFailure:
+		reported = lastFailure
+	default:
+		reported = last
+	}
+	// Un timeout siempre es el resultado del job, con cualquier política:
+	// un comando colgado no puede quedar oculto tras otro que terminó bien
+	if timedOut != nil {
+		reported = timedOut
+	}
+	if reported != nil {
+		result.ExitCode = reported.ExitCode
+		result.Status = reported.Status
+	}
+
+	maskedOut.Flush()
+
 	// Escribir timestamp de fin
 	timestamp = time.Now().Format("2006-01-02 15:04:05")
-	file.WriteString(fmt.Sprintf("\n=== Ejecución finalizada: %s (código: %d) ===\n\n", timestamp, lastExitCode))
-	logger.DebugLog("Job '%s': ejecución finalizada con código de salida: %d", job.Name, lastExitCode)
+	fmt.Fprintf(out, "\n=== Ejecución finalizada: %s%s (código: %d, estado: %s) ===\n\n", timestamp, attemptLabel, result.ExitCode, result.Status)
+	logger.DebugLog("Job '%s': ejecución finalizada con código de salida: %d (%s)", job.Name, result.ExitCode, result.Status)
+
+	return result, nil
+}
 
-	return lastExitCode, nil
+// stepResult es el resultado de un comando individual
+type stepResult struct {
+	ExitCode int
+	Status   string
+}
+
+// runStep ejecuta un comando del job y registra su resultado en el log
+func runStep(ctx context.Context, out io.Writer, job config.Job, label string, c config.Command, grace time.Duration, env []string) stepResult {
+	logger.DebugLog("Job '%s': ejecutando %s: %s", job.Name, label, c.Run)
+	fmt.Fprintf(out, "\n[%s] %s\n", label, c.Run)
+
+	timeout, err := commandTimeout(job, c)
+	if err != nil {
+		logger.DebugLog("Job '%s': timeout inválido en %s: %v", job.Name, label, err)
+		fmt.Fprintf(out, "\n[ERROR] Timeout inválido: %v\n", err)
+		return stepResult{ExitCode: 1, Status: StatusFailed}
+	}
+
+	cmd, err := buildCommand(job, c.Run, env)
+	if err != nil {
+		logger.DebugLog("Job '%s': no se pudo preparar %s: %v", job.Name, label, err)
+		fmt.Fprintf(out, "\n[ERROR] Error ejecutando comando: %v\n", err)
+		return stepResult{ExitCode: 1, Status: StatusFailed}
+	}
+
+	exitCode, err := runCommand(ctx, cmd, timeout, grace, out)
+	switch {
+	case errors.Is(err, errTimeout):
+		logger.DebugLog("Job '%s': %s excedió el timeout de %s", job.Name, label, timeout)
+		fmt.Fprintf(out, "\n[TIMEOUT] Comando excedió el timeout de %s, procesos terminados\n", timeout)
+		return stepResult{ExitCode: ExitCodeTimeout, Status: StatusTimeout}
+	case errors.Is(err, errCanceled):
+		logger.DebugLog("Job '%s': %s cancelado", job.Name, label)
+		io.WriteString(out, "\n[CANCELADO] Ejecución cancelada, procesos terminados\n")
+		return stepResult{ExitCode: ExitCodeCanceled, Status: StatusCanceled}
+	case err != nil:
+		logger.DebugLog("Job '%s': error ejecutando %s: %v", job.Name, label, err)
+		fmt.Fprintf(out, "\n[ERROR] Error ejecutando comando: %v\n", err)
+		return stepResult{ExitCode: 1, Status: StatusFailed}
+	case exitCode != 0:
+		logger.DebugLog("Job '%s': %s falló con código de salida: %d", job.Name, label, exitCode)
+		fmt.Fprintf(out, "\n[ERROR] Comando falló con código de salida: %d\n", exitCode)
+		return stepResult{ExitCode: exitCode, Status: StatusFailed}
+	default:
+		logger.DebugLog("Job '%s': %s completado exitosamente", job.Name, label)
+		io.WriteString(out, "\n[OK] Comando completado exitosamente\n")
+		return stepResult{ExitCode: 0, Status: StatusSuccess}
+	}
+}
+
+// runCommand ejecuta un comando en su propio grupo de procesos. Si excede el
+// timeout o se cancela el contexto, envía SIGTERM a todo el grupo y SIGKILL
+// pasado el periodo de gracia.
+func runCommand(ctx context.Context, cmd *exec.Cmd, timeout, grace time.Duration, out io.Writer) (int, error) {
+	if ctx.Err() != nil {
+		return ExitCodeCanceled, errCanceled
+	}
+
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	// Si la salida no es un archivo, no esperar indefinidamente a procesos
+	// en segundo plano que hereden el pipe
+	cmd.WaitDelay = outputWaitDelay
+
+	if err := cmd.Start(); err != nil {
+		return 1, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	var reason error
+	select {
+	case err := <-done:
+		if err == nil {
+			return 0, nil
+		}
+		if exitError, ok := err.(*exec.ExitError); ok {
+			return exitError.ExitCode(), nil
+		}
+		if errors.Is(err, exec.ErrWaitDelay) {
+			// El comando terminó pero un proceso en segundo plano mantenía la salida abierta
+			return cmd.ProcessState.ExitCode(), nil
+		}
+		return 1, err
+	case <-expired:
+		reason = errTimeout
+	case <-ctx.Done():
+		reason = errCanceled
+	}
+
+	pgid := cmd.Process.Pid
+	syscall.Kill(-pgid, syscall.SIGTERM)
+	select {
+	case <-done:
+		// Asegurar que no queden procesos del grupo vivos
+		syscall.Kill(-pgid, syscall.SIGKILL)
+	case <-time.After(grace):
+		syscall.Kill(-pgid, syscall.SIGKILL)
+		<-done
+	}
+	if reason == errTimeout {
+		return ExitCodeTimeout, reason
+	}
+	return ExitCodeCanceled, reason
+}
+
+// commandTimeout retorna el timeout efectivo de un comando (0 = sin límite)
+func commandTimeout(job config.Job, c config.Command) (time.Duration, error) {
+	if c.Timeout != "" {
+		return config.ParseDuration(c.Timeout)
+	}
+	return config.ParseDuration(job.Timeout)
 }
 
 // GetLogPath retorna la ruta del archivo de log de un job
@@ -86,3 +379,83 @@ func GetLogPath(jobName string) (string, error) {
 	return filepath.Join(logsDir, jobName+".log"), nil
 }
 
+// AppendLog agrega una línea al log de un job fuera de una ejecución
+// (ej. ejecuciones omitidas o encoladas por la política de concurrencia)
+func AppendLog(jobName string, message string) error {
+	if err := config.EnsureLogsDir(); err != nil {
+		return err
+	}
+	logPath, err := GetLogPath(jobName)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error abriendo archivo de log: %w", err)
+	}
+	defer file.Close()
+
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	if _, err := file.WriteString(fmt.Sprintf("\n=== %s: %s ===\n", message, timestamp)); err != nil {
+		return fmt.Errorf("error escribiendo log: %w", err)
+	}
+	return nil
+}
+
+// tailBuffer conserva los últimos limit bytes escritos
+type tailBuffer struct {
+	limit int
+	buf   []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	// Compactar solo cuando el exceso es grande para no copiar en cada escritura
+	if len(t.buf) > 2*t.limit {
+		t.buf = append([]byte(nil), t.buf[len(t.buf)-t.limit:]...)
+	}
+	return len(p), nil
+}
+
+// Bytes retorna los últimos limit bytes
+func (t *tailBuffer) Bytes() []byte {
+	if len(t.buf) > t.limit {
+		return t.buf[len(t.buf)-t.limit:]
+	}
+	return t.buf
+}
+
+// ArchiveLog mueve el log de un job a logs/archive/<job>-<timestamp>.log.
+// Retorna la ruta del archivo archivado o "" si el job no tenía log.
+func ArchiveLog(jobName string) (string, error) {
+	logPath, err := GetLogPath(jobName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(logPath); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	archiveDir := filepath.Join(filepath.Dir(logPath), ArchiveDir)
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return "", fmt.Errorf("error creando directorio de archivo: %w", err)
+	}
+
+	archivePath := filepath.Join(archiveDir, fmt.Sprintf("%s-%s.log", jobName, time.Now().Format("20060102-150405")))
+	if err := os.Rename(logPath, archivePath); err != nil {
+		return "", fmt.Errorf("error archivando log: %w", err)
+	}
+	return archivePath, nil
+}
+
+// DeleteLog elimina el log de un job si existe
+func DeleteLog(jobName string) error {
+	logPath, err := GetLogPath(jobName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(logPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error eliminando log: %w", err)
+	}
+	return nil
+}
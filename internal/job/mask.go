@@ -0,0 +1,94 @@
+package job
+
+import (
+	"bytes"
+	"io"
+	"sort"
+)
+
+// maskedValue reemplaza en los logs los valores de las variables de entorno
+const maskedValue = "****"
+
+// maxPendingLine es el máximo de bytes retenidos esperando un fin de línea
+const maxPendingLine = 4096
+
+// maskWriter enmascara valores secretos en la salida. Trabaja por líneas para
+// que un valor partido entre dos escrituras también se enmascare; en líneas
+// de más de maxPendingLine bytes retiene solo el final que podría ser el
+// comienzo de un valor.
+type maskWriter struct {
+	w       io.Writer
+	secrets [][]byte
+	pending []byte
+}
+
+func newMaskWriter(w io.Writer, secrets []string) *maskWriter {
+	m := &maskWriter{w: w}
+	for _, s := range secrets {
+		if s != "" {
+			m.secrets = append(m.secrets, []byte(s))
+		}
+	}
+	// Los valores más largos primero, por si uno contiene a otro
+	sort.Slice(m.secrets, func(i, j int) bool { return len(m.secrets[i]) > len(m.secrets[j]) })
+	return m
+}
+
+func (m *maskWriter) Write(p []byte) (int, error) {
+	if len(m.secrets) == 0 {
+		return m.w.Write(p)
+	}
+	m.pending = append(m.pending, p...)
+	end := bytes.LastIndexByte(m.pending, '\n') + 1
+	if end == 0 && len(m.pending) < maxPendingLine {
+		return len(p), nil
+	}
+	if end == 0 {
+		// Línea demasiado larga: se escribe, pero se retiene el final por si
+		// ahí empieza un valor que termina en la próxima escritura
+		end = m.safeCut()
+		if end == 0 {
+			return len(p), nil
+		}
+	}
+	if _, err := m.w.Write(m.mask(m.pending[:end])); err != nil {
+		return 0, err
+	}
+	m.pending = append(m.pending[:0], m.pending[end:]...)
+	return len(p), nil
+}
+
+// safeCut retorna hasta dónde se puede escribir lo retenido sin partir un
+// valor secreto: se guardan los últimos len(secreto más largo)-1 bytes y,
+// si un valor cruza el corte, se corta antes de él
+func (m *maskWriter) safeCut() int {
+	end := len(m.pending) - (len(m.secrets[0]) - 1)
+	for moved := true; moved && end > 0; {
+		moved = false
+		for _, secret := range m.secrets {
+			from := max(end-len(secret)+1, 0)
+			if i := bytes.Index(m.pending[from:min(end+len(secret)-1, len(m.pending))], secret); i >= 0 && from+i < end {
+				end = from + i
+				moved = true
+			}
+		}
+	}
+	return max(end, 0)
+}
+
+// Flush escribe lo retenido de una línea incompleta
+func (m *maskWriter) Flush() error {
+	if len(m.pending) == 0 {
+		return nil
+	}
+	_, err := m.w.Write(m.mask(m.pending))
+	m.pending = m.pending[:0]
+	return err
+}
+
+func (m *maskWriter) mask(p []byte) []byte {
+	for _, secret := range m.secrets {
+		p = bytes.ReplaceAll(p, secret, []byte(maskedValue))
+	}
+	return p
+}
@@ -0,0 +1,156 @@
+package job
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+)
+
+// buildCommand prepara el exec.Cmd de un comando según el shell y el
+// directorio de trabajo del job
+func buildCommand(job config.Job, cmdStr string, env []string) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	switch shell := job.Shell; shell {
+	case "", config.ShellDefault:
+		cmd = exec.Command(config.ShellDefault, "-c", cmdStr)
+	case config.ShellNone:
+		// Sin shell: el comando se separa en argumentos respetando comillas
+		args, err := SplitArgs(cmdStr)
+		if err != nil {
+			return nil, fmt.Errorf("comando inválido: %w", err)
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("comando vacío")
+		}
+		cmd = exec.Command(args[0], args[1:]...)
+	default:
+		cmd = exec.Command(shell, "-c", cmdStr)
+	}
+	// exec.Command busca el programa con el PATH del daemon; el entorno del
+	// job puede definir otro PATH
+	if path, ok := lookPathIn(cmd.Args[0], env); ok {
+		cmd.Path, cmd.Err = path, nil
+	}
+	if cmd.Err != nil {
+		return nil, cmd.Err
+	}
+	cmd.Env = env
+
+	if job.Workdir != "" {
+		dir, err := ResolveWorkdir(job.Workdir)
+		if err != nil {
+			return nil, err
+		}
+		cmd.Dir = dir
+	}
+	return cmd, nil
+}
+
+// ResolveWorkdir expande ~ y resuelve rutas relativas desde el home. El
+// directorio debe existir.
+func ResolveWorkdir(workdir string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo directorio home: %w", err)
+	}
+	dir := workdir
+	switch {
+	case dir == "~" || strings.HasPrefix(dir, "~/"):
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	case !filepath.IsAbs(dir):
+		dir = filepath.Join(home, dir)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("workdir inválido: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("workdir inválido: %s no es un directorio", dir)
+	}
+	return dir, nil
+}
+
+// lookPathIn busca un programa en el PATH de un entorno
+func lookPathIn(name string, env []string) (string, bool) {
+	if strings.Contains(name, "/") {
+		return "", false
+	}
+	for i := len(env) - 1; i >= 0; i-- {
+		value, ok := strings.CutPrefix(env[i], "PATH=")
+		if !ok {
+			continue
+		}
+		for _, dir := range filepath.SplitList(value) {
+			if dir == "" {
+				dir = "."
+			}
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+				return path, true
+			}
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// SplitArgs separa un comando en argumentos como lo haría sh, sin expandir
+// variables ni comodines: admite comillas simples, dobles y escapes con \
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+		case quote == '"':
+			switch {
+			case c == '"':
+				quote = 0
+			case c == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0:
+				i++
+				current.WriteByte(s[i])
+			default:
+				current.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == '\\':
+			if i+1 < len(s) {
+				i++
+				current.WriteByte(s[i])
+			}
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteByte(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("comillas sin cerrar")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,411 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	weekdayNames  = []string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"}
+	weekdayPlural = []string{"domingos", "lunes", "martes", "miércoles", "jueves", "viernes", "sábados"}
+	weekdayAbbr   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+	monthNames    = []string{"", "enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+	monthAbbr     = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+)
+
+// Describe retorna una descripción en lenguaje natural de una expresión de
+// schedule (ej. "de lunes a viernes a las 08:00")
+func Describe(expr string) (string, error) {
+	if _, err := Parse(expr); err != nil {
+		return "", err
+	}
+
+	spec := Normalize(expr)
+	timezone := EffectiveTimezone(spec, "")
+	if timezone != "" {
+		_, spec, _ = strings.Cut(spec, " ")
+		spec = strings.TrimSpace(spec)
+	}
+	desc := describeSpec(spec)
+	if timezone != "" {
+		desc += " (hora de " + timezone + ")"
+	}
+	return desc, nil
+}
+
+// Next retorna las próximas n ejecuciones de una expresión a partir de from,
+// en la zona horaria en la que se interpreta
+func Next(expr, timezone string, from time.Time, n int) ([]time.Time, error) {
+	sched, err := ParseIn(expr, timezone)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := LoadLocation(EffectiveTimezone(expr, timezone))
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]time.Time, 0, max(n, 0))
+	t := from
+	for i := 0; i < n; i++ {
+		t = sched.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t.In(loc))
+	}
+	return times, nil
+}
+
+func describeSpec(spec string) string {
+	switch spec {
+	case "@yearly", "@annually":
+		return "el 1 de enero a las 00:00"
+	case "@monthly":
+		return "el día 1 de cada mes a las 00:00"
+	case "@weekly":
+		return "los domingos a las 00:00"
+	case "@daily", "@midnight":
+		return "todos los días a las 00:00"
+	case "@hourly":
+		return "cada hora, en el minuto 0"
+	}
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return spec
+		}
+		return describeDuration(d)
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 6 {
+		return spec
+	}
+	timePart, fixed := describeTime(fields[0], fields[1], fields[2])
+	dayPart := describeDays(fields[3], fields[4], fields[5])
+	switch {
+	case fixed && dayPart == "":
+		return "todos los días " + timePart
+	case fixed:
+		return dayPart + " " + timePart
+	case dayPart == "":
+		return timePart
+	default:
+		return timePart + ", " + dayPart
+	}
+}
+
+// describeDuration describe el intervalo de un @every (ej. "cada 1 hora y 30 minutos")
+func describeDuration(d time.Duration) string {
+	units := []struct {
+		size      time.Duration
+		one, many string
+	}{
+		{24 * time.Hour, "día", "días"},
+		{time.Hour, "hora", "horas"},
+		{time.Minute, "minuto", "minutos"},
+		{time.Second, "segundo", "segundos"},
+	}
+	var parts []string
+	for _, u := range units {
+		if n := d / u.size; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, plural(int(n), u.one, u.many)))
+			d -= n * u.size
+		}
+	}
+	switch {
+	case len(parts) == 0:
+		return "cada " + d.String()
+	case len(parts) == 1 && strings.HasPrefix(parts[0], "1 "):
+		return "cada " + strings.TrimPrefix(parts[0], "1 ")
+	}
+	return "cada " + joinList(parts)
+}
+
+// describeTime describe los campos de segundo, minuto y hora. fixed indica
+// que son horas concretas del día ("a las 08:00 y 18:00")
+func describeTime(sec, min, hour string) (string, bool) {
+	secN, secFixed := number(sec)
+	minN, minFixed := number(min)
+	if secFixed && minFixed {
+		var clocks []string
+		for _, h := range strings.Split(hour, ",") {
+			hourN, ok := number(h)
+			if !ok {
+				clocks = nil
+				break
+			}
+			clock := fmt.Sprintf("%02d:%02d", hourN, minN)
+			if secN != 0 {
+				clock += fmt.Sprintf(":%02d", secN)
+			}
+			clocks = append(clocks, clock)
+		}
+		if clocks != nil {
+			return "a las " + joinList(clocks), true
+		}
+	}
+
+	var parts []string
+	switch {
+	case sec == "0":
+	case sec == "*":
+		parts = append(parts, "cada segundo")
+	default:
+		parts = append(parts, describeField(sec, "segundo", "segundos"))
+	}
+
+	_, minList := numbers(min)
+	switch {
+	case min == "*" && sec == "0":
+		parts = append(parts, "cada minuto")
+	case min == "*" && secFixed:
+		parts = append(parts, "de cada minuto")
+	case min == "*":
+	default:
+		parts = append(parts, describeField(min, "minuto", "minutos"))
+	}
+
+	switch {
+	case hour == "*" && minList:
+		parts = append(parts, "de cada hora")
+	case hour == "*":
+	default:
+		parts = append(parts, describeHours(hour))
+	}
+
+	text := ""
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			text = part
+		case strings.HasPrefix(part, "de "):
+			text += " " + part
+		default:
+			text += ", " + part
+		}
+	}
+	return text, false
+}
+
+// describeField describe un campo numérico genérico (segundos, minutos, días del mes)
+func describeField(field, one, many string) string {
+	items := parseField(field)
+	if len(items) == 1 && items[0].step > 0 {
+		return describeStep(items[0], one, many, func(v string) string { return "del " + one + " " + v })
+	}
+
+	var texts []string
+	for _, item := range items {
+		switch {
+		case item.step > 0:
+			texts = append(texts, describeStep(item, one, many, func(v string) string { return "del " + one + " " + v }))
+		case item.end != "":
+			texts = append(texts, fmt.Sprintf("del %s al %s", item.start, item.end))
+		default:
+			texts = append(texts, item.start)
+		}
+	}
+	if len(items) == 1 && items[0].end == "" && items[0].step == 0 {
+		return "en el " + one + " " + texts[0]
+	}
+	return "en los " + many + " " + joinList(texts)
+}
+
+// describeStep describe un elemento con paso (*/n, a/n, a-b/n)
+func describeStep(item fieldItem, one, many string, from func(string) string) string {
+	text := "cada " + one
+	if item.step != 1 {
+		text = fmt.Sprintf("cada %d %s", item.step, many)
+	}
+	switch {
+	case item.all:
+		return text
+	case item.end != "":
+		return fmt.Sprintf("%s, del %s al %s", text, item.start, item.end)
+	default:
+		return text + " a partir " + from(item.start)
+	}
+}
+
+// describeHours describe el campo de hora cuando no son horas concretas
+func describeHours(field string) string {
+	var texts []string
+	for _, item := range parseField(field) {
+		start, _ := number(item.start)
+		end := start
+		if item.end != "" {
+			end, _ = number(item.end)
+		}
+		window := fmt.Sprintf("entre las %02d:00 y las %02d:59", start, end)
+		switch {
+		case item.step > 0 && item.all:
+			texts = append(texts, describeStep(item, "hora", "horas", nil))
+		case item.step > 0 && item.end != "":
+			texts = append(texts, describeStep(fieldItem{all: true, step: item.step}, "hora", "horas", nil)+" "+window)
+		case item.step > 0:
+			texts = append(texts, describeStep(item, "hora", "horas", func(v string) string { return fmt.Sprintf("de las %02d:00", start) }))
+		default:
+			texts = append(texts, window)
+		}
+	}
+	return joinList(texts)
+}
+
+// describeDays describe los campos de día del mes, mes y día de la semana
+func describeDays(dom, month, dow string) string {
+	var domPart, dowPart, monthPart string
+	if dom != "*" && dom != "?" {
+		domPart = strings.Replace(describeField(dom, "día", "días"), "en ", "", 1)
+	}
+	if dow != "*" && dow != "?" {
+		dowPart = describeWeekdays(dow)
+	}
+	if month != "*" {
+		monthPart = describeMonths(month)
+	}
+
+	days := domPart
+	switch {
+	case domPart != "" && dowPart != "":
+		// Con ambos campos restringidos basta con que se cumpla uno
+		days = domPart + " o " + dowPart
+	case dowPart != "":
+		days = dowPart
+	}
+	switch {
+	case monthPart == "":
+		if domPart != "" && dowPart == "" && !strings.HasPrefix(domPart, "cada") {
+			days += " de cada mes"
+		}
+		return days
+	case days == "":
+		return "todos los días " + monthPart
+	case domPart != "" && dowPart == "" && strings.HasPrefix(monthPart, "en "):
+		return days + " de " + strings.TrimPrefix(monthPart, "en ")
+	default:
+		return days + ", " + monthPart
+	}
+}
+
+// describeWeekdays describe el campo de día de la semana
+func describeWeekdays(field string) string {
+	var singles, texts []string
+	for _, item := range parseField(field) {
+		start := nameAt(item.start, weekdayAbbr, weekdayNames, 0)
+		switch {
+		case item.step > 0:
+			texts = append(texts, describeStep(item, "día de la semana", "días de la semana", func(string) string { return "del " + start }))
+		case item.end != "":
+			texts = append(texts, fmt.Sprintf("de %s a %s", start, nameAt(item.end, weekdayAbbr, weekdayNames, 0)))
+		default:
+			singles = append(singles, nameAt(item.start, weekdayAbbr, weekdayPlural, 0))
+		}
+	}
+	if len(singles) > 0 {
+		texts = append([]string{"los " + joinList(singles)}, texts...)
+	}
+	return joinList(texts)
+}
+
+// describeMonths describe el campo de mes
+func describeMonths(field string) string {
+	var singles, texts []string
+	for _, item := range parseField(field) {
+		start := nameAt(item.start, monthAbbr, monthNames, 1)
+		switch {
+		case item.step > 0:
+			texts = append(texts, describeStep(item, "mes", "meses", func(string) string { return "de " + start }))
+		case item.end != "":
+			texts = append(texts, fmt.Sprintf("de %s a %s", start, nameAt(item.end, monthAbbr, monthNames, 1)))
+		default:
+			singles = append(singles, start)
+		}
+	}
+	if len(singles) > 0 {
+		texts = append([]string{"en " + joinList(singles)}, texts...)
+	}
+	return joinList(texts)
+}
+
+// fieldItem es un elemento de un campo cron separado por comas: un valor
+// (start), un rango (start-end) o todos (*), con un paso opcional
+type fieldItem struct {
+	start, end string
+	all        bool
+	step       int
+}
+
+func parseField(field string) []fieldItem {
+	var items []fieldItem
+	for _, part := range strings.Split(field, ",") {
+		var item fieldItem
+		base, step, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			item.step, _ = strconv.Atoi(step)
+		}
+		switch {
+		case base == "*" || base == "?":
+			item.all = true
+		case strings.Contains(base, "-"):
+			item.start, item.end, _ = strings.Cut(base, "-")
+		default:
+			item.start = base
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// nameAt traduce un valor (número o abreviatura en inglés) al nombre en español
+func nameAt(value string, abbr, names []string, offset int) string {
+	if n, ok := number(value); ok && n < len(names) {
+		return names[n]
+	}
+	for i, a := range abbr {
+		if i >= offset && strings.EqualFold(a, value) {
+			return names[i]
+		}
+	}
+	return value
+}
+
+// number interpreta un valor numérico simple
+func number(value string) (int, bool) {
+	n, err := strconv.Atoi(value)
+	return n, err == nil
+}
+
+// numbers indica si un campo es una lista de valores simples
+func numbers(field string) ([]int, bool) {
+	var values []int
+	for _, part := range strings.Split(field, ",") {
+		n, ok := number(part)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, n)
+	}
+	return values, true
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// joinList une elementos al estilo "a, b y c"
+func joinList(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " y " + items[len(items)-1]
+}
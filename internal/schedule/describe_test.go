@@ -0,0 +1,97 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"minuto con paso", "*/15 * * * *", "cada 15 minutos"},
+		{"minuto fijo", "30 * * * *", "en el minuto 30 de cada hora"},
+		{"lista de minutos", "0,30 * * * *", "en los minutos 0 y 30 de cada hora"},
+		{"rango de minutos", "10-20 * * * *", "en los minutos del 10 al 20"},
+		{"cada minuto", "* * * * *", "cada minuto"},
+		{"hora fija", "0 8 * * *", "todos los días a las 08:00"},
+		{"lista de horas", "0 8,18 * * *", "todos los días a las 08:00 y 18:00"},
+		{"hora con paso", "0 */2 * * *", "en el minuto 0, cada 2 horas"},
+		{"rango de horas", "0 9-17 * * *", "en el minuto 0, entre las 09:00 y las 17:59"},
+		{"rango de horas con paso", "0 8-18/2 * * *", "en el minuto 0, cada 2 horas entre las 08:00 y las 18:59"},
+		{"día del mes", "0 0 1 * *", "el día 1 de cada mes a las 00:00"},
+		{"lista de días del mes", "0 0 1,15 * *", "los días 1 y 15 de cada mes a las 00:00"},
+		{"día del mes con paso", "0 0 */2 * *", "cada 2 días a las 00:00"},
+		{"mes por nombre", "0 0 1 jan *", "el día 1 de enero a las 00:00"},
+		{"rango de meses", "0 0 * 1-3 *", "todos los días de enero a marzo a las 00:00"},
+		{"rango de días de la semana", "0 8 * * 1-5", "de lunes a viernes a las 08:00"},
+		{"días de la semana por nombre", "0 8 * * mon,wed,fri", "los lunes, miércoles y viernes a las 08:00"},
+		{"domingo como 0", "0 8 * * 0", "los domingos a las 08:00"},
+		{"día del mes o de la semana", "0 0 13 * 5", "el día 13 o los viernes a las 00:00"},
+		{"@daily", "@daily", "todos los días a las 00:00"},
+		{"@hourly", "@hourly", "cada hora, en el minuto 0"},
+		{"@weekly", "@weekly", "los domingos a las 00:00"},
+		{"@every compuesto", "@every 90m", "cada 1 hora y 30 minutos"},
+		{"@every de una unidad", "@every 1h", "cada hora"},
+		{"@every en segundos", "@every 30s", "cada 30 segundos"},
+		{"zona horaria", "CRON_TZ=America/Santo_Domingo 0 2 * * *", "todos los días a las 02:00 (hora de America/Santo_Domingo)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Describe(tt.expr)
+			if err != nil {
+				t.Fatalf("Describe(%q): %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Describe(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		message string
+	}{
+		{"nunca se ejecuta", "0 0 30 2 *", "nunca se ejecuta"},
+		{"fuera de rango", "61 * * * *", "schedule inválido"},
+		{"zona desconocida", "CRON_TZ=Mars/Olympus 0 2 * * *", "Mars/Olympus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Describe(tt.expr); err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Describe(%q) = %v, want %q", tt.expr, err, tt.message)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	from := time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC)
+
+	times, err := Next("0 8,18 * * *", "America/Santo_Domingo", from, 3)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	// 10:30 UTC son las 06:30 en Santo Domingo
+	want := []string{"2026-01-01 08:00 AST", "2026-01-01 18:00 AST", "2026-01-02 08:00 AST"}
+	if len(times) != len(want) {
+		t.Fatalf("%d ejecuciones, want %d: %v", len(times), len(want), times)
+	}
+	for i, tm := range times {
+		if got := tm.Format("2006-01-02 15:04 MST"); got != want[i] {
+			t.Errorf("ejecución %d = %s, want %s", i, got, want[i])
+		}
+	}
+
+	if _, err := Next("0 0 30 2 *", "", from, 3); err == nil || !strings.Contains(err.Error(), "nunca se ejecuta") {
+		t.Errorf("Next de una fecha imposible = %v, want error \"nunca se ejecuta\"", err)
+	}
+}
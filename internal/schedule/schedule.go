@@ -0,0 +1,103 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// parser acepta los mismos formatos que el scheduler: cron de 6 campos (con
+// segundos) y descriptores (@every, @daily...). Los cron de 5 campos se
+// normalizan antes de parsear.
+var parser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Normalize normaliza una expresión cron para que funcione con segundos.
+// Si es una expresión de 5 campos (sin segundos), agrega "0" al inicio. Un
+// prefijo de zona horaria (CRON_TZ=... o TZ=...) se conserva.
+func Normalize(expr string) string {
+	expr = strings.TrimSpace(expr)
+	if hasTimezone(expr) {
+		prefix, rest, found := strings.Cut(expr, " ")
+		if !found {
+			return expr
+		}
+		return prefix + " " + Normalize(rest)
+	}
+
+	// Si ya empieza con @, es un intervalo especial, no necesita normalización
+	if strings.HasPrefix(expr, "@") {
+		return expr
+	}
+
+	// Contar campos separados por espacios
+	fields := strings.Fields(expr)
+	if len(fields) == 5 {
+		// Es una expresión cron estándar sin segundos, agregar "0" al inicio
+		return "0 " + expr
+	}
+
+	// Ya tiene 6 campos o es inválida, retornar tal cual
+	return expr
+}
+
+// Parse interpreta una expresión de schedule en la hora local
+func Parse(expr string) (cron.Schedule, error) {
+	return ParseIn(expr, "")
+}
+
+// ParseIn interpreta una expresión de schedule en una zona horaria de la base
+// de datos del sistema (ej. America/Santo_Domingo). Si la expresión ya tiene
+// su propio prefijo CRON_TZ= se respeta ese.
+func ParseIn(expr, timezone string) (cron.Schedule, error) {
+	spec := Normalize(expr)
+	if hasTimezone(spec) && !strings.Contains(spec, " ") {
+		return nil, fmt.Errorf("schedule inválido '%s': falta la expresión después de la zona horaria", expr)
+	}
+	if timezone != "" && !hasTimezone(spec) {
+		if _, err := LoadLocation(timezone); err != nil {
+			return nil, err
+		}
+		spec = "CRON_TZ=" + timezone + " " + spec
+	}
+
+	sched, err := parser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("schedule inválido '%s': %w", expr, err)
+	}
+	// Una fecha imposible (ej. 30 de febrero) se acepta al interpretarla pero
+	// nunca se ejecutaría
+	if sched.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("schedule inválido '%s': nunca se ejecuta (¿fecha imposible?)", expr)
+	}
+	return sched, nil
+}
+
+// LoadLocation valida una zona horaria contra la base de datos del sistema
+func LoadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("zona horaria inválida '%s': %w", timezone, err)
+	}
+	return loc, nil
+}
+
+// EffectiveTimezone retorna la zona horaria en la que se interpreta una
+// expresión: la de su prefijo CRON_TZ= si tiene, o timezone en caso contrario
+func EffectiveTimezone(expr, timezone string) string {
+	expr = strings.TrimSpace(expr)
+	if !hasTimezone(expr) {
+		return timezone
+	}
+	prefix, _, _ := strings.Cut(expr, " ")
+	_, tz, _ := strings.Cut(prefix, "=")
+	return tz
+}
+
+func hasTimezone(expr string) bool {
+	return strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=")
+}
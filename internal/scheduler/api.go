@@ -0,0 +1,281 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/history"
+	"github.com/osmargm1202/orgmcron/internal/logger"
+)
+
+// SocketFile es el socket Unix (en el directorio de ejecución) donde el daemon
+// atiende la API de control local
+const SocketFile = "orgmcron.sock"
+
+// EntryStatus es el estado de un job programado en el daemon
+type EntryStatus struct {
+	Job         string     `json:"job"`
+	Schedule    string     `json:"schedule"`
+	Timezone    string     `json:"timezone,omitempty"`
+	Next        *time.Time `json:"next,omitempty"`
+	Prev        *time.Time `json:"prev,omitempty"`
+	Paused      bool       `json:"paused"`
+	Running     int        `json:"running"`
+	Queued      int        `json:"queued"`
+	Skipped     int        `json:"skipped"`
+	LastSkipped *time.Time `json:"last_skipped,omitempty"`
+}
+
+// DaemonStatus es el estado general del daemon
+type DaemonStatus struct {
+	PID        int           `json:"pid"`
+	StartedAt  time.Time     `json:"started_at"`
+	ActiveRuns int           `json:"active_runs"`
+	Entries    []EntryStatus `json:"entries"`
+}
+
+// jobRequest es el cuerpo de las operaciones sobre un job
+type jobRequest struct {
+	Job             string `json:"job"`
+	SkipHealthcheck bool   `json:"skip_healthcheck,omitempty"`
+	// Until (RFC 3339) y Reason acompañan a /pause, como --until y --reason
+	Until  string `json:"until,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// Command es el comando que hizo el pedido, para el snapshot de la
+	// configuración (ej. "pause backup --until 2h")
+	Command string `json:"command,omitempty"`
+}
+
+// apiError es el cuerpo de las respuestas con error
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// GetSocketPath retorna la ruta del socket de la API
+func GetSocketPath() (string, error) {
+	runtimeDir, err := config.GetRuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(runtimeDir, SocketFile), nil
+}
+
+// ServeAPI atiende la API de control en el socket Unix hasta que el scheduler
+// se detiene. Falla si otro daemon ya está atendiendo el socket.
+func (s *Scheduler) ServeAPI() error {
+	if err := config.EnsureRuntimeDir(); err != nil {
+		return err
+	}
+	socketPath, err := GetSocketPath()
+	if err != nil {
+		return err
+	}
+
+	// Un socket que no acepta conexiones es de un daemon que terminó sin limpiar
+	if conn, err := net.Dial("unix", socketPath); err == nil {
+		conn.Close()
+		return fmt.Errorf("ya hay un daemon atendiendo %s", socketPath)
+	}
+	os.Remove(socketPath)
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("error abriendo socket de control: %w", err)
+	}
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("error ajustando permisos del socket: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/entries", s.handleEntries)
+	mux.HandleFunc("/run", s.handleRun)
+	mux.HandleFunc("/pause", s.handlePause)
+	mux.HandleFunc("/resume", s.handleResume)
+	mux.HandleFunc("/reload", s.handleReload)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	// Se cierra al terminar Start, así la API sigue respondiendo mientras se
+	// esperan las ejecuciones en curso
+	s.closeAPI = func() {
+		server.Close()
+		os.Remove(socketPath)
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.DebugLog("Error en la API de control: %v", err)
+			fmt.Fprintf(os.Stderr, "Error en la API de control: %v\n", err)
+		}
+	}()
+
+	logger.DebugLog("API de control escuchando en %s", socketPath)
+	return nil
+}
+
+// Status retorna el estado del daemon y de sus jobs programados
+func (s *Scheduler) Status() DaemonStatus {
+	return DaemonStatus{
+		PID:        os.Getpid(),
+		StartedAt:  s.startedAt,
+		ActiveRuns: s.activeRuns(),
+		Entries:    s.Entries(),
+	}
+}
+
+// Entries retorna el estado de los jobs programados, ordenados por nombre
+func (s *Scheduler) Entries() []EntryStatus {
+	now := time.Now()
+	s.mu.RLock()
+	entries := make([]EntryStatus, 0, len(s.jobs))
+	for name, scheduled := range s.jobs {
+		entry := EntryStatus{Job: name, Schedule: scheduled.Job.Schedule, Timezone: scheduled.Job.Timezone, Paused: scheduled.Job.IsPaused(now)}
+		cronEntry := s.cron.Entry(scheduled.ID)
+		if !cronEntry.Next.IsZero() {
+			next := cronEntry.Next
+			entry.Next = &next
+		}
+		if !cronEntry.Prev.IsZero() {
+			prev := cronEntry.Prev
+			entry.Prev = &prev
+		}
+		entries = append(entries, entry)
+	}
+	s.mu.RUnlock()
+
+	for i := range entries {
+		stats := s.Stats(entries[i].Job)
+		entries[i].Running = stats.Running
+		entries[i].Queued = stats.Queued
+		entries[i].Skipped = stats.Skipped
+		if !stats.LastSkipped.IsZero() {
+			entries[i].LastSkipped = &stats.LastSkipped
+		}
+	}
+	sort.Slice(entries, func(a, b int) bool { return entries[a].Job < entries[b].Job })
+	return entries
+}
+
+func (s *Scheduler) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Status())
+}
+
+func (s *Scheduler) handleEntries(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Entries())
+}
+
+func (s *Scheduler) handleRun(w http.ResponseWriter, r *http.Request) {
+	req, ok := readJobRequest(w, r)
+	if !ok {
+		return
+	}
+	logger.DebugLog("Solicitud de ejecución inmediata para job '%s'", req.Job)
+	fmt.Fprintf(os.Stdout, "[%s] Ejecución inmediata solicitada\n", req.Job)
+	if err := s.RunNow(req.Job, RunOptions{SkipHealthcheck: req.SkipHealthcheck, Trigger: history.TriggerDaemon}); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, struct{}{})
+}
+
+func (s *Scheduler) handlePause(w http.ResponseWriter, r *http.Request) {
+	req, ok := readJobRequest(w, r)
+	if !ok {
+		return
+	}
+	var until time.Time
+	if req.Until != "" {
+		var err error
+		if until, err = time.Parse(time.RFC3339, req.Until); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("until inválido: %w", err))
+			return
+		}
+	}
+	if err := s.Pause(req.Job, PauseOptions{Until: until, Reason: req.Reason, Command: req.Command}); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errNotScheduled) {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Scheduler) handleResume(w http.ResponseWriter, r *http.Request) {
+	req, ok := readJobRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Resume(req.Job, req.Command); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errNotScheduled) {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Scheduler) handleReload(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	logger.DebugLog("Recarga solicitada por la API")
+	fmt.Fprintf(os.Stdout, "Recarga solicitada, recargando configuración...\n")
+	summary, err := s.Reload()
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+func requireMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("método %s no permitido, se espera %s", r.Method, method))
+		return false
+	}
+	return true
+}
+
+func readJobRequest(w http.ResponseWriter, r *http.Request) (jobRequest, bool) {
+	var req jobRequest
+	if !requireMethod(w, r, http.MethodPost) {
+		return req, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("solicitud inválida: %w", err))
+		return req, false
+	}
+	if req.Job == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("falta el nombre del job"))
+		return req, false
+	}
+	return req, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, apiError{Error: err.Error()})
+}
@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrAPIUnavailable indica que no hay un daemon atendiendo el socket de control
+var ErrAPIUnavailable = errors.New("el daemon no está atendiendo el socket de control")
+
+// Client habla con la API de control del daemon a través del socket Unix
+type Client struct {
+	http *http.Client
+}
+
+// NewClient crea un cliente para la API del daemon. Retorna ErrAPIUnavailable
+// si el socket no existe.
+func NewClient() (*Client, error) {
+	socketPath, err := GetSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(socketPath); err != nil {
+		return nil, ErrAPIUnavailable
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socketPath)
+		},
+	}
+	return &Client{http: &http.Client{Transport: transport, Timeout: 30 * time.Second}}, nil
+}
+
+// Status retorna el estado del daemon
+func (c *Client) Status() (*DaemonStatus, error) {
+	var status DaemonStatus
+	if err := c.do(http.MethodGet, "/status", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Entries retorna los jobs programados en el daemon
+func (c *Client) Entries() ([]EntryStatus, error) {
+	var entries []EntryStatus
+	if err := c.do(http.MethodGet, "/entries", nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// RunNow pide al daemon que ejecute un job de inmediato
+func (c *Client) RunNow(name string, skipHealthcheck bool) error {
+	return c.do(http.MethodPost, "/run", jobRequest{Job: name, SkipHealthcheck: skipHealthcheck}, nil)
+}
+
+// Pause pausa un job en el daemon; el snapshot de la configuración registra
+// el comando de este proceso
+func (c *Client) Pause(name string, until time.Time, reason string) error {
+	req := jobRequest{Job: name, Reason: reason, Command: strings.Join(os.Args[1:], " ")}
+	if !until.IsZero() {
+		req.Until = until.Format(time.RFC3339)
+	}
+	return c.do(http.MethodPost, "/pause", req, nil)
+}
+
+// Resume reanuda un job pausado en el daemon
+func (c *Client) Resume(name string) error {
+	return c.do(http.MethodPost, "/resume", jobRequest{Job: name, Command: strings.Join(os.Args[1:], " ")}, nil)
+}
+
+// Reload pide al daemon que recargue la configuración y retorna el resumen
+func (c *Client) Reload() (*ReloadSummary, error) {
+	var summary ReloadSummary
+	if err := c.do(http.MethodPost, "/reload", nil, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// do envía una solicitud a la API y decodifica la respuesta en out
+func (c *Client) do(method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error serializando solicitud: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	// El host es ignorado: la conexión siempre va al socket
+	req, err := http.NewRequest(method, "http://orgmcron"+path, reader)
+	if err != nil {
+		return fmt.Errorf("error creando solicitud: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return ErrAPIUnavailable
+		}
+		return fmt.Errorf("error comunicándose con el daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr apiError
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Error == "" {
+			return fmt.Errorf("el daemon respondió %s", resp.Status)
+		}
+		return errors.New(apiErr.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error leyendo respuesta del daemon: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/job"
+	"github.com/osmargm1202/orgmcron/internal/logger"
+	"github.com/robfig/cron/v3"
+)
+
+// jobState guarda el estado de ejecución de un job. Vive en el Scheduler y no
+// en el cron, por lo que se conserva entre recargas.
+type jobState struct {
+	// exclusive lo mantiene la ejecución en curso con concurrency skip/queue/replace
+	exclusive sync.Mutex
+
+	mu          sync.Mutex
+	running     int
+	queued      int
+	skipped     int
+	lastSkipped time.Time
+	cancel      context.CancelFunc
+	// replacements numera los disparos con concurrency replace; el que espera
+	// su turno solo corre si sigue siendo el último
+	replacements uint64
+}
+
+// JobStats resume el estado de concurrencia de un job
+type JobStats struct {
+	Running     int
+	Queued      int
+	Skipped     int
+	LastSkipped time.Time
+}
+
+// state retorna (creando si hace falta) el estado de un job
+func (s *Scheduler) state(name string) *jobState {
+	s.statesMu.Lock()
+	defer s.statesMu.Unlock()
+	st, ok := s.states[name]
+	if !ok {
+		st = &jobState{}
+		s.states[name] = st
+	}
+	return st
+}
+
+// Stats retorna el estado de concurrencia de un job
+func (s *Scheduler) Stats(name string) JobStats {
+	st := s.state(name)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return JobStats{
+		Running:     st.running,
+		Queued:      st.queued,
+		Skipped:     st.skipped,
+		LastSkipped: st.lastSkipped,
+	}
+}
+
+// activeRuns retorna el número total de ejecuciones en curso
+func (s *Scheduler) activeRuns() int {
+	s.statesMu.Lock()
+	states := make([]*jobState, 0, len(s.states))
+	for _, st := range s.states {
+		states = append(states, st)
+	}
+	s.statesMu.Unlock()
+
+	total := 0
+	for _, st := range states {
+		st.mu.Lock()
+		total += st.running
+		st.mu.Unlock()
+	}
+	return total
+}
+
+// concurrencyWrapper aplica la política de concurrencia del job, al estilo de
+// cron.SkipIfStillRunning / cron.DelayIfStillRunning pero registrando en el log
+// del job y exponiendo el estado en Stats.
+func (s *Scheduler) concurrencyWrapper(j config.Job) cron.JobWrapper {
+	return func(inner cron.Job) cron.Job {
+		return cron.FuncJob(func() {
+			st := s.state(j.Name)
+
+			switch j.Concurrency {
+			case config.ConcurrencySkip:
+				if !st.exclusive.TryLock() {
+					st.mu.Lock()
+					st.skipped++
+					st.lastSkipped = time.Now()
+					st.mu.Unlock()
+					logger.DebugLog("Job '%s': ejecución omitida, la anterior sigue en curso", j.Name)
+					fmt.Fprintf(os.Stdout, "[%s] Ejecución omitida: la anterior sigue en curso\n", j.Name)
+					job.AppendLog(j.Name, "Ejecución omitida (concurrency: skip, la anterior sigue en curso)")
+					return
+				}
+				defer st.exclusive.Unlock()
+
+			case config.ConcurrencyQueue:
+				if !st.exclusive.TryLock() {
+					st.mu.Lock()
+					st.queued++
+					st.mu.Unlock()
+					logger.DebugLog("Job '%s': ejecución encolada, la anterior sigue en curso", j.Name)
+					fmt.Fprintf(os.Stdout, "[%s] Ejecución encolada: la anterior sigue en curso\n", j.Name)
+					job.AppendLog(j.Name, "Ejecución encolada (concurrency: queue, la anterior sigue en curso)")
+					st.exclusive.Lock()
+					st.mu.Lock()
+					st.queued--
+					st.mu.Unlock()
+				}
+				defer st.exclusive.Unlock()
+
+			case config.ConcurrencyReplace:
+				st.mu.Lock()
+				st.replacements++
+				turn := st.replacements
+				st.mu.Unlock()
+				if !st.exclusive.TryLock() {
+					st.mu.Lock()
+					if st.cancel != nil {
+						st.cancel()
+					}
+					st.mu.Unlock()
+					logger.DebugLog("Job '%s': cancelando la ejecución en curso para reemplazarla", j.Name)
+					fmt.Fprintf(os.Stdout, "[%s] Cancelando la ejecución en curso para reemplazarla\n", j.Name)
+					st.exclusive.Lock()
+
+					// Si mientras se esperaba llegó otro disparo, gana el más nuevo
+					st.mu.Lock()
+					superseded := st.replacements != turn
+					st.mu.Unlock()
+					if superseded {
+						st.exclusive.Unlock()
+						logger.DebugLog("Job '%s': ejecución reemplazada antes de empezar", j.Name)
+						job.AppendLog(j.Name, "Ejecución reemplazada antes de empezar (concurrency: replace, llegó un disparo más nuevo)")
+						return
+					}
+				}
+				defer st.exclusive.Unlock()
+			}
+
+			inner.Run()
+		})
+	}
+}
+
+// beginRun registra el inicio de una ejecución y retorna su contexto
+func (s *Scheduler) beginRun(name string) (context.Context, func()) {
+	st := s.state(name)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	st.mu.Lock()
+	st.running++
+	st.cancel = cancel
+	st.mu.Unlock()
+
+	return ctx, func() {
+		cancel()
+		st.mu.Lock()
+		st.running--
+		st.mu.Unlock()
+	}
+}
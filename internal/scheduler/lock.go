@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/logger"
+)
+
+// locksDir es el subdirectorio de ejecución con un lock por job en curso
+const locksDir = "locks"
+
+// acquireRunLock marca un job como en ejecución mientras dure la ejecución.
+// Se usa un flock compartido, así varias ejecuciones en paralelo (del daemon o
+// de `orgmcron run`) pueden tenerlo a la vez y el kernel lo libera si el
+// proceso muere.
+func acquireRunLock(name string) func() {
+	lockPath, err := getLockPath(name)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(lockPath), 0700)
+	}
+	if err != nil {
+		logger.DebugLog("Error creando lock de ejecución para '%s': %v", name, err)
+		return func() {}
+	}
+
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		logger.DebugLog("Error abriendo lock de ejecución para '%s': %v", name, err)
+		return func() {}
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_SH); err != nil {
+		logger.DebugLog("Error tomando lock de ejecución para '%s': %v", name, err)
+		file.Close()
+		return func() {}
+	}
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}
+}
+
+// IsRunning indica si un job se está ejecutando en algún proceso
+func IsRunning(name string) bool {
+	lockPath, err := getLockPath(name)
+	if err != nil {
+		return false
+	}
+	file, err := os.Open(lockPath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		return errors.Is(err, syscall.EWOULDBLOCK)
+	}
+	syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	return false
+}
+
+func getLockPath(name string) (string, error) {
+	runtimeDir, err := config.GetRuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(runtimeDir, locksDir, name+".lock"), nil
+}
@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/job"
+	"github.com/osmargm1202/orgmcron/internal/logger"
+	"github.com/osmargm1202/orgmcron/internal/validate"
+	"github.com/robfig/cron/v3"
+)
+
+// errNotScheduled indica que el job pedido no está programado en el daemon
+var errNotScheduled = errors.New("no está programado")
+
+// PauseOptions son las opciones de una pausa pedida al daemon
+type PauseOptions struct {
+	// Until reanuda el job a esa hora; cero conserva la que tenga
+	Until time.Time
+	// Reason es el motivo de la pausa; vacío conserva el que tenga
+	Reason string
+	// Command se registra en el snapshot de la configuración
+	Command string
+}
+
+// Pause pausa un job igual que 'orgmcron pause': lo guarda en su
+// configuración (enabled: false) y lo aplica solo a ese job, así la pausa se
+// ve en list, sobrevive a un reinicio y desaparece si se elimina el job. Las
+// ejecuciones en curso no se interrumpen y `run` sigue funcionando.
+func (s *Scheduler) Pause(name string, opts PauseOptions) error {
+	command := opts.Command
+	if command == "" {
+		command = fmt.Sprintf("pause %s (API)", name)
+	}
+	return s.modifyScheduled(name, command, func(j *config.Job) {
+		enabled := false
+		j.Enabled = &enabled
+		if !opts.Until.IsZero() {
+			j.PausedUntil = opts.Until.Format(time.RFC3339)
+		}
+		if opts.Reason != "" {
+			j.PauseReason = opts.Reason
+		}
+	})
+}
+
+// Resume reanuda un job pausado, igual que 'orgmcron resume'
+func (s *Scheduler) Resume(name, command string) error {
+	if command == "" {
+		command = fmt.Sprintf("resume %s (API)", name)
+	}
+	return s.modifyScheduled(name, command, func(j *config.Job) {
+		j.Enabled = nil
+		j.PausedUntil = ""
+		j.PauseReason = ""
+	})
+}
+
+// modifyScheduled guarda un cambio de un job programado y lo aplica a ese
+// job sin recargar toda la configuración: un error en otro job no debe
+// impedir pausarlo. El cambio no toca el schedule, así que su entrada en el
+// cron se conserva.
+func (s *Scheduler) modifyScheduled(name, command string, fn func(*config.Job)) error {
+	s.mu.RLock()
+	_, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("job '%s' %w", name, errNotScheduled)
+	}
+
+	var updated config.Job
+	err := config.ModifyJobFor(command, name, func(j *config.Job) error {
+		fn(j)
+		if err := validate.Job(*j); err != nil {
+			return fmt.Errorf("job inválido: %w", err)
+		}
+		updated = *j
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error guardando job: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scheduled, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("job '%s' %w", name, errNotScheduled)
+	}
+	// Con la zona horaria efectiva, como en LoadJobs, para que la próxima
+	// recarga lo vea sin cambios
+	updated.Timezone = scheduled.Job.Timezone
+	hash, err := jobHash(updated)
+	if err != nil {
+		return err
+	}
+	scheduled.Job, scheduled.Hash = updated, hash
+	s.jobs[name] = scheduled
+
+	if updated.IsPaused(time.Now()) {
+		logger.DebugLog("Job '%s' pausado", name)
+		fmt.Fprintf(os.Stdout, "[%s] Job pausado\n", name)
+	} else {
+		logger.DebugLog("Job '%s' reanudado", name)
+		fmt.Fprintf(os.Stdout, "[%s] Job reanudado\n", name)
+	}
+	return nil
+}
+
+// isPaused indica si un job está pausado según su definición actual
+// (enabled: false, hasta paused_until si lo tiene), que una pausa desde la
+// API actualiza sin reprogramarlo
+func (s *Scheduler) isPaused(name string) bool {
+	s.mu.RLock()
+	scheduled, ok := s.jobs[name]
+	s.mu.RUnlock()
+	return ok && scheduled.Job.IsPaused(time.Now())
+}
+
+// pauseWrapper omite las ejecuciones programadas de un job pausado
+func (s *Scheduler) pauseWrapper(j config.Job) cron.JobWrapper {
+	return func(next cron.Job) cron.Job {
+		return cron.FuncJob(func() {
+			if s.isPaused(j.Name) {
+				logger.DebugLog("Job '%s' pausado, se omite la ejecución programada", j.Name)
+				fmt.Fprintf(os.Stdout, "[%s] Job pausado, se omite la ejecución\n", j.Name)
+				job.AppendLog(j.Name, "Ejecución omitida (job pausado)")
+				return
+			}
+			next.Run()
+		})
+	}
+}
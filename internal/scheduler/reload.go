@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+)
+
+// ReloadSummary resume los cambios aplicados en una recarga
+type ReloadSummary struct {
+	Added     []string          `json:"added"`
+	Updated   []string          `json:"updated"`
+	Removed   []string          `json:"removed"`
+	Unchanged []string          `json:"unchanged"`
+	Failed    map[string]string `json:"failed,omitempty"`
+}
+
+// String retorna el resumen en una línea
+func (r *ReloadSummary) String() string {
+	parts := []string{
+		fmt.Sprintf("%d agregados%s", len(r.Added), nameList(r.Added)),
+		fmt.Sprintf("%d actualizados%s", len(r.Updated), nameList(r.Updated)),
+		fmt.Sprintf("%d eliminados%s", len(r.Removed), nameList(r.Removed)),
+		fmt.Sprintf("%d sin cambios", len(r.Unchanged)),
+	}
+	if len(r.Failed) > 0 {
+		names := make([]string, 0, len(r.Failed))
+		for name := range r.Failed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts = append(parts, fmt.Sprintf("%d con errores%s", len(names), nameList(names)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func nameList(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(names, ", ") + ")"
+}
+
+// jobHash calcula un hash del contenido de un job para detectar cambios
+func jobHash(j config.Job) (string, error) {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return "", fmt.Errorf("error calculando hash del job: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
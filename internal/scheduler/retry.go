@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/job"
+	"github.com/osmargm1202/orgmcron/internal/logger"
+)
+
+const (
+	// DefaultRetryDelay es el delay inicial si la política no define initial_delay
+	DefaultRetryDelay = 30 * time.Second
+	// DefaultRetryMultiplier es el factor de backoff si la política no define multiplier
+	DefaultRetryMultiplier = 2.0
+)
+
+// executeWithRetry ejecuta un job aplicando su política de reintentos.
+// Retorna el resultado del último intento.
+func executeWithRetry(ctx context.Context, j config.Job, runOpts RunOptions) (*job.Result, error) {
+	policy := j.Retry
+	maxAttempts := maxAttempts(j)
+
+	delay, maxDelay, multiplier, err := retryDelays(policy)
+	if err != nil {
+		return nil, fmt.Errorf("política de reintentos inválida: %w", err)
+	}
+
+	opts := job.Options{Context: ctx, MaxAttempts: maxAttempts, Stream: runOpts.Stream}
+	if j.HealthcheckAttachOutput && j.HealthcheckURL != "" {
+		opts.OutputLimit = j.HealthcheckOutputLimit
+		if opts.OutputLimit <= 0 {
+			opts.OutputLimit = config.DefaultOutputLimit
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		opts.Attempt = attempt
+		result, err := job.Execute(j, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.ExitCode == 0 || result.Status == job.StatusCanceled || attempt >= maxAttempts || !isRetryable(policy, result.ExitCode) {
+			return result, nil
+		}
+
+		logger.DebugLog("Job '%s': intento %d/%d falló con código %d, reintentando en %s", j.Name, attempt, maxAttempts, result.ExitCode, delay)
+		fmt.Fprintf(os.Stdout, "[%s] Intento %d/%d falló (código %d), reintentando en %s\n", j.Name, attempt, maxAttempts, result.ExitCode, delay)
+
+		// Esperar el backoff salvo que el scheduler se detenga
+		select {
+		case <-time.After(delay):
+		case <-runOpts.Stop:
+			logger.DebugLog("Job '%s': reintentos cancelados, scheduler detenido", j.Name)
+			return result, nil
+		case <-ctx.Done():
+			logger.DebugLog("Job '%s': reintentos cancelados", j.Name)
+			return result, nil
+		}
+
+		delay = time.Duration(float64(delay) * multiplier)
+		if maxDelay > 0 && delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+// maxAttempts retorna el número total de intentos de un job
+func maxAttempts(j config.Job) int {
+	if j.Retry != nil && j.Retry.MaxAttempts > 1 {
+		return j.Retry.MaxAttempts
+	}
+	return 1
+}
+
+// retryDelays interpreta los delays de la política aplicando los valores por defecto
+func retryDelays(policy *config.RetryPolicy) (time.Duration, time.Duration, float64, error) {
+	if policy == nil {
+		return 0, 0, 0, nil
+	}
+
+	delay, err := config.ParseDuration(policy.InitialDelay)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("initial_delay: %w", err)
+	}
+	if policy.InitialDelay == "" {
+		delay = DefaultRetryDelay
+	}
+
+	maxDelay, err := config.ParseDuration(policy.MaxDelay)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("max_delay: %w", err)
+	}
+	if maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = DefaultRetryMultiplier
+	}
+
+	return delay, maxDelay, multiplier, nil
+}
+
+// isRetryable indica si un código de salida puede reintentarse según la política
+func isRetryable(policy *config.RetryPolicy, exitCode int) bool {
+	if policy == nil {
+		return false
+	}
+	if len(policy.ExitCodes) == 0 {
+		return true
+	}
+	for _, code := range policy.ExitCodes {
+		if code == exitCode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/healthcheck"
+	"github.com/osmargm1202/orgmcron/internal/history"
+	"github.com/osmargm1202/orgmcron/internal/job"
+	"github.com/osmargm1202/orgmcron/internal/logger"
+)
+
+// RunOptions controla una ejecución de un job
+type RunOptions struct {
+	// Stream recibe una copia de la salida además del log del job
+	Stream io.Writer
+	// SkipHealthcheck evita enviar pings al healthcheck
+	SkipHealthcheck bool
+	// Stop interrumpe la espera entre reintentos (ej. al detener el daemon)
+	Stop <-chan struct{}
+	// Trigger es el origen de la ejecución para el historial (default schedule)
+	Trigger string
+}
+
+// Run ejecuta un job (con reintentos) y notifica el healthcheck siguiendo el
+// protocolo de healthchecks.io: /start al iniciar, URL base si termina bien y
+// /<código> (o /fail) si falla. Es el mismo camino que usa el daemon, de modo
+// que `orgmcron run` se comporta igual que una ejecución programada. Cada
+// ejecución queda registrada en el historial.
+func Run(ctx context.Context, j config.Job, pingKey string, opts RunOptions) (*job.Result, error) {
+	// Visible para `orgmcron status` desde otros procesos
+	release := acquireRunLock(j.Name)
+	defer release()
+
+	record := history.Record{
+		Job:         j.Name,
+		Trigger:     opts.Trigger,
+		Start:       time.Now(),
+		MaxAttempts: maxAttempts(j),
+	}
+	if record.Trigger == "" {
+		record.Trigger = history.TriggerSchedule
+	}
+	if j.HealthcheckURL != "" && opts.SkipHealthcheck {
+		record.Healthcheck = history.HealthcheckSkipped
+	}
+	defer func() {
+		record.End = time.Now()
+		record.Duration = record.End.Sub(record.Start).Seconds()
+		if err := history.Append(record); err != nil {
+			logger.DebugLog("Error registrando ejecución de '%s' en el historial: %v", j.Name, err)
+			fmt.Fprintf(os.Stderr, "[%s] Error registrando en el historial: %v\n", j.Name, err)
+		}
+	}()
+
+	logger.DebugLog("Ejecutando job: %s (schedule: %s)", j.Name, j.Schedule)
+	fmt.Fprintf(os.Stdout, "[%s] Ejecutando job: %s\n", j.Schedule, j.Name)
+
+	if opts.SkipHealthcheck {
+		j.HealthcheckURL = ""
+	}
+
+	if j.HealthcheckURL != "" {
+		if err := healthcheck.SendStart(j.HealthcheckURL, pingKey); err != nil {
+			logger.DebugLog("Error enviando inicio al healthcheck para job '%s': %v", j.Name, err)
+			fmt.Fprintf(os.Stderr, "[%s] Error enviando inicio al healthcheck: %v\n", j.Name, err)
+		}
+	}
+
+	result, err := executeWithRetry(ctx, j, opts)
+	if err != nil {
+		logger.DebugLog("Error ejecutando job '%s': %v", j.Name, err)
+		fmt.Fprintf(os.Stderr, "[%s] Error ejecutando job: %v\n", j.Name, err)
+		record.ExitCode = -1
+		record.Status = job.StatusFailed
+		record.Error = err.Error()
+		if j.HealthcheckURL != "" {
+			setHealthcheckResult(&record, reportFailure(j, pingKey, -1, nil))
+		}
+		return nil, err
+	}
+	exitCode := result.ExitCode
+	record.ExitCode = exitCode
+	record.Status = result.Status
+	record.Attempt = result.Attempt
+
+	logger.DebugLog("Job '%s' completado con código de salida: %d", j.Name, exitCode)
+
+	switch {
+	case result.Status == job.StatusCanceled:
+		// La ejecución fue reemplazada por una nueva, que reportará su propio resultado
+		logger.DebugLog("Job '%s' cancelado, no se envía healthcheck", j.Name)
+		fmt.Fprintf(os.Stdout, "[%s] Ejecución cancelada, no se envía healthcheck\n", j.Name)
+	case exitCode == 0:
+		if j.HealthcheckURL == "" {
+			break
+		}
+		logger.DebugLog("Enviando healthcheck para job '%s' a URL: %s", j.Name, j.HealthcheckURL)
+		err := healthcheck.SendHealthcheck(j.HealthcheckURL, pingKey, result.Output)
+		if err != nil {
+			logger.DebugLog("Error enviando healthcheck para job '%s': %v", j.Name, err)
+			fmt.Fprintf(os.Stderr, "[%s] Error enviando healthcheck: %v\n", j.Name, err)
+		} else {
+			logger.DebugLog("Healthcheck enviado exitosamente para job '%s'", j.Name)
+			fmt.Fprintf(os.Stdout, "[%s] Healthcheck enviado exitosamente\n", j.Name)
+		}
+		setHealthcheckResult(&record, err)
+	default:
+		if result.Status == job.StatusTimeout {
+			logger.DebugLog("Job '%s' excedió su timeout (código %d)", j.Name, exitCode)
+			fmt.Fprintf(os.Stderr, "[%s] Job excedió su timeout (código %d)\n", j.Name, exitCode)
+		} else {
+			logger.DebugLog("Job '%s' falló con código %d", j.Name, exitCode)
+			fmt.Fprintf(os.Stderr, "[%s] Job falló con código %d\n", j.Name, exitCode)
+		}
+		if j.HealthcheckURL != "" {
+			setHealthcheckResult(&record, reportFailure(j, pingKey, exitCode, result.Output))
+		}
+	}
+
+	return result, nil
+}
+
+// reportFailure notifica un fallo al healthcheck del job, si tiene uno
+func reportFailure(j config.Job, pingKey string, exitCode int, output []byte) error {
+	if j.HealthcheckURL == "" {
+		return nil
+	}
+	err := healthcheck.SendFailure(j.HealthcheckURL, pingKey, exitCode, output)
+	if err != nil {
+		logger.DebugLog("Error enviando fallo al healthcheck para job '%s': %v", j.Name, err)
+		fmt.Fprintf(os.Stderr, "[%s] Error enviando fallo al healthcheck: %v\n", j.Name, err)
+	} else {
+		logger.DebugLog("Fallo notificado al healthcheck para job '%s' (código %d)", j.Name, exitCode)
+		fmt.Fprintf(os.Stdout, "[%s] Fallo notificado al healthcheck\n", j.Name)
+	}
+	return err
+}
+
+// setHealthcheckResult registra en el historial el resultado del ping final
+func setHealthcheckResult(record *history.Record, err error) {
+	if err != nil {
+		record.Healthcheck = history.HealthcheckError
+		record.HealthcheckError = err.Error()
+		return
+	}
+	record.Healthcheck = history.HealthcheckOK
+}
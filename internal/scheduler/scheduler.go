@@ -4,24 +4,40 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/osmargm1202/orgmcron/internal/config"
-	"github.com/osmargm1202/orgmcron/internal/healthcheck"
-	"github.com/osmargm1202/orgmcron/internal/job"
+	"github.com/osmargm1202/orgmcron/internal/history"
 	"github.com/osmargm1202/orgmcron/internal/logger"
+	"github.com/osmargm1202/orgmcron/internal/schedule"
+	"github.com/osmargm1202/orgmcron/internal/validate"
 	"github.com/robfig/cron/v3"
 )
 
+// scheduledJob es un job programado en el cron
+type scheduledJob struct {
+	ID  cron.EntryID
+	Job config.Job
+	// Hash de la definición del job, para detectar cambios al recargar
+	Hash string
+}
+
 type Scheduler struct {
 	cron      *cron.Cron
-	jobs      map[string]cron.EntryID
+	jobs      map[string]scheduledJob
 	mu        sync.RWMutex
 	pingKey   string
 	stopChan  chan struct{}
 	reloadChan chan struct{}
+	startedAt time.Time
+	// closeAPI detiene la API de control, si se inició con ServeAPI
+	closeAPI func()
+
+	// Estado de ejecución por job, se conserva entre recargas
+	states   map[string]*jobState
+	statesMu sync.Mutex
 }
 
 // NewScheduler crea un nuevo scheduler
@@ -31,110 +47,163 @@ func NewScheduler(pingKey string) *Scheduler {
 	c := cron.New(cron.WithSeconds())
 	return &Scheduler{
 		cron:       c,
-		jobs:       make(map[string]cron.EntryID),
+		jobs:       make(map[string]scheduledJob),
 		pingKey:    pingKey,
 		stopChan:   make(chan struct{}),
 		reloadChan: make(chan struct{}),
+		states:     make(map[string]*jobState),
+		startedAt:  time.Now(),
 	}
 }
 
-// LoadJobs carga los jobs desde la configuración y los programa
-func (s *Scheduler) LoadJobs() error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
+// LoadJobs carga los jobs desde la configuración y aplica solo las
+// diferencias con lo programado actualmente: los jobs nuevos se agregan, los
+// eliminados se quitan y los modificados (según el hash de su definición) se
+// reemplazan. Los jobs sin cambios conservan su entrada en el cron, por lo que
+// sus timers (@every) no se reinician. Si la configuración no puede leerse se
+// mantiene la programación actual.
+func (s *Scheduler) LoadJobs() (*ReloadSummary, error) {
 	logger.DebugLog("Iniciando carga de jobs")
 
-	// Detener y limpiar jobs existentes
-	s.cron.Stop()
-	// Usar WithSeconds para soportar expresiones con segundos
-	s.cron = cron.New(cron.WithSeconds())
-	s.jobs = make(map[string]cron.EntryID)
-
 	// Cargar configuración
-	config, err := config.LoadJobs()
+	jobsConfig, err := config.LoadJobs()
 	if err != nil {
 		logger.DebugLog("Error cargando jobs: %v", err)
-		return fmt.Errorf("error cargando jobs: %w", err)
+		return nil, fmt.Errorf("error cargando jobs: %w", err)
 	}
+	logger.DebugLog("Cargados %d jobs desde la configuración", len(jobsConfig.Jobs))
 
-	logger.DebugLog("Cargados %d jobs desde la configuración", len(config.Jobs))
+	// La pingkey también puede haber cambiado
+	appConfig, err := config.LoadConfig()
+	if err != nil {
+		logger.DebugLog("Error cargando configuración: %v", err)
+		return nil, fmt.Errorf("error cargando configuración: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pingKey = appConfig.PingKey
+	summary := &ReloadSummary{Failed: map[string]string{}}
 
-	// Programar cada job
-	for _, j := range config.Jobs {
-		if err := s.scheduleJob(j); err != nil {
+	// Quitar los jobs que ya no existen
+	desired := make(map[string]bool, len(jobsConfig.Jobs))
+	for _, j := range jobsConfig.Jobs {
+		desired[j.Name] = true
+	}
+	for name, scheduled := range s.jobs {
+		if !desired[name] {
+			s.cron.Remove(scheduled.ID)
+			delete(s.jobs, name)
+			summary.Removed = append(summary.Removed, name)
+			fmt.Fprintf(os.Stdout, "Job '%s' eliminado del schedule\n", name)
+		}
+	}
+
+	// Agregar los nuevos y reemplazar los modificados
+	for _, j := range jobsConfig.Jobs {
+		// La zona horaria global forma parte de la definición efectiva del job
+		j.Timezone = schedule.EffectiveTimezone(j.Schedule, appConfig.TimezoneFor(j))
+		hash, err := jobHash(j)
+		if err != nil {
+			summary.Failed[j.Name] = err.Error()
+			continue
+		}
+
+		existing, exists := s.jobs[j.Name]
+		if exists && existing.Hash == hash {
+			summary.Unchanged = append(summary.Unchanged, j.Name)
+			continue
+		}
+
+		// Programar la nueva definición antes de quitar la anterior, así un
+		// error deja el job funcionando con su definición previa
+		entryID, err := s.addEntry(j)
+		if err != nil {
 			logger.DebugLog("Error programando job '%s': %v", j.Name, err)
 			fmt.Fprintf(os.Stderr, "Error programando job '%s': %v\n", j.Name, err)
+			summary.Failed[j.Name] = err.Error()
 			continue
 		}
+		if exists {
+			s.cron.Remove(existing.ID)
+			summary.Updated = append(summary.Updated, j.Name)
+		} else {
+			summary.Added = append(summary.Added, j.Name)
+		}
+		s.jobs[j.Name] = scheduledJob{ID: entryID, Job: j, Hash: hash}
 		logger.DebugLog("Job '%s' programado exitosamente con schedule '%s'", j.Name, j.Schedule)
+		if j.Timezone != "" {
+			fmt.Fprintf(os.Stdout, "Job '%s' programado con schedule '%s' (%s)\n", j.Name, j.Schedule, j.Timezone)
+		} else {
+			fmt.Fprintf(os.Stdout, "Job '%s' programado con schedule '%s'\n", j.Name, j.Schedule)
+		}
 	}
 
-	// Iniciar el cron
+	if running := s.activeRuns(); running > 0 {
+		// Las ejecuciones en curso no se interrumpen; su estado vive en el
+		// Scheduler, así que la política de concurrencia se sigue respetando
+		logger.DebugLog("Recarga con %d ejecuciones en curso, se conservan", running)
+		fmt.Fprintf(os.Stdout, "Recarga: %d ejecuciones en curso continúan sin interrupción\n", running)
+	}
+
+	// Iniciar el cron (no hace nada si ya está corriendo)
 	s.cron.Start()
-	logger.DebugLog("Scheduler iniciado con %d jobs programados", len(s.jobs))
-	return nil
+	logger.DebugLog("Recarga aplicada: %s", summary)
+	fmt.Fprintf(os.Stdout, "Recarga aplicada: %s\n", summary)
+	return summary, nil
 }
 
-// normalizeSchedule normaliza una expresión cron para que funcione con WithSeconds
-// Si es una expresión de 5 campos (sin segundos), agrega "0" al inicio
-func normalizeSchedule(schedule string) string {
-	// Si ya empieza con @, es un intervalo especial, no necesita normalización
-	if strings.HasPrefix(schedule, "@") {
-		return schedule
-	}
-	
-	// Contar campos separados por espacios
-	fields := strings.Fields(schedule)
-	if len(fields) == 5 {
-		// Es una expresión cron estándar sin segundos, agregar "0" al inicio
-		return "0 " + schedule
-	}
-	
-	// Ya tiene 6 campos o es inválida, retornar tal cual
-	return schedule
+// addEntry agrega un job al cron con su política de concurrencia
+func (s *Scheduler) addEntry(j config.Job) (cron.EntryID, error) {
+	sched, err := schedule.ParseIn(j.Schedule, j.Timezone)
+	if err != nil {
+		return 0, fmt.Errorf("error agregando job al cron: %w", err)
+	}
+
+	switch j.Concurrency {
+	case "", config.ConcurrencyAllow, config.ConcurrencySkip, config.ConcurrencyQueue, config.ConcurrencyReplace:
+	default:
+		return 0, fmt.Errorf("concurrency inválido: %s", j.Concurrency)
+	}
+	if _, err := j.PausedUntilTime(); err != nil {
+		return 0, err
+	}
+
+	run := cron.FuncJob(func() {
+		s.runJob(j, RunOptions{Trigger: history.TriggerSchedule})
+	})
+	return s.cron.Schedule(sched, cron.NewChain(s.pauseWrapper(j), s.concurrencyWrapper(j)).Then(run)), nil
 }
 
-// scheduleJob programa un job individual
-func (s *Scheduler) scheduleJob(j config.Job) error {
-	// Normalizar el schedule para que funcione con WithSeconds
-	normalizedSchedule := normalizeSchedule(j.Schedule)
-	
-	entryID, err := s.cron.AddFunc(normalizedSchedule, func() {
-		logger.DebugLog("Ejecutando job: %s (schedule: %s)", j.Name, j.Schedule)
-		fmt.Fprintf(os.Stdout, "[%s] Ejecutando job: %s\n", j.Schedule, j.Name)
-		
-		exitCode, err := job.Execute(j, s.pingKey)
-		if err != nil {
-			logger.DebugLog("Error ejecutando job '%s': %v", j.Name, err)
-			fmt.Fprintf(os.Stderr, "[%s] Error ejecutando job: %v\n", j.Name, err)
-			return
-		}
+// runJob ejecuta un job programado llevando el registro de su estado
+func (s *Scheduler) runJob(j config.Job, opts RunOptions) {
+	ctx, done := s.beginRun(j.Name)
+	defer done()
 
-		logger.DebugLog("Job '%s' completado con código de salida: %d", j.Name, exitCode)
+	s.mu.RLock()
+	pingKey := s.pingKey
+	s.mu.RUnlock()
 
-		// Solo enviar healthcheck si el job fue exitoso
-		if exitCode == 0 && j.HealthcheckURL != "" {
-			logger.DebugLog("Enviando healthcheck para job '%s' a URL: %s", j.Name, j.HealthcheckURL)
-			if err := healthcheck.SendHealthcheck(j.HealthcheckURL, s.pingKey); err != nil {
-				logger.DebugLog("Error enviando healthcheck para job '%s': %v", j.Name, err)
-				fmt.Fprintf(os.Stderr, "[%s] Error enviando healthcheck: %v\n", j.Name, err)
-			} else {
-				logger.DebugLog("Healthcheck enviado exitosamente para job '%s'", j.Name)
-				fmt.Fprintf(os.Stdout, "[%s] Healthcheck enviado exitosamente\n", j.Name)
-			}
-		} else if exitCode != 0 {
-			logger.DebugLog("Job '%s' falló con código %d, no se envía healthcheck", j.Name, exitCode)
-			fmt.Fprintf(os.Stderr, "[%s] Job falló con código %d, no se envía healthcheck\n", j.Name, exitCode)
-		}
-	})
+	opts.Stop = s.stopChan
+	Run(ctx, j, pingKey, opts)
+}
 
-	if err != nil {
-		return fmt.Errorf("error agregando job al cron: %w", err)
+// RunNow ejecuta un job programado de inmediato, en segundo plano y aplicando
+// su política de concurrencia
+func (s *Scheduler) RunNow(name string, opts RunOptions) error {
+	s.mu.RLock()
+	scheduled, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("job '%s' no está programado", name)
 	}
 
-	s.jobs[j.Name] = entryID
-	fmt.Fprintf(os.Stdout, "Job '%s' programado con schedule '%s'\n", j.Name, j.Schedule)
+	j := scheduled.Job
+	run := cron.FuncJob(func() {
+		s.runJob(j, opts)
+	})
+	go cron.NewChain(s.concurrencyWrapper(j)).Then(run).Run()
 	return nil
 }
 
@@ -145,8 +214,17 @@ func (s *Scheduler) UpdatePingKey(pingKey string) {
 	s.pingKey = pingKey
 }
 
-// Reload recarga los jobs desde la configuración
-func (s *Scheduler) Reload() error {
+// Reload valida la configuración en disco y, si es válida, recarga los jobs.
+// Una configuración inválida se rechaza completa y se mantiene la programación actual.
+func (s *Scheduler) Reload() (*ReloadSummary, error) {
+	report, err := validate.Check()
+	if err == nil {
+		err = report.Err()
+	}
+	if err != nil {
+		logger.DebugLog("Configuración rechazada: %v", err)
+		return nil, fmt.Errorf("configuración rechazada, se mantiene la programación actual: %w", err)
+	}
 	return s.LoadJobs()
 }
 
@@ -158,11 +236,49 @@ func (s *Scheduler) Stop() {
 	close(s.stopChan)
 }
 
+// waitForRuns espera a que terminen las ejecuciones en curso. Una nueva señal
+// de parada corta la espera.
+func (s *Scheduler) waitForRuns(sigChan <-chan os.Signal) {
+	running := s.activeRuns()
+	if running == 0 {
+		return
+	}
+	logger.DebugLog("Esperando %d ejecuciones en curso antes de salir", running)
+	fmt.Fprintf(os.Stdout, "Esperando a que terminen %d ejecuciones en curso (envía otra señal para salir de inmediato)...\n", running)
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for s.activeRuns() > 0 {
+		select {
+		case <-ticker.C:
+		case sig := <-sigChan:
+			if sig == syscall.SIGINT || sig == syscall.SIGTERM {
+				logger.DebugLog("Recibida señal %v, saliendo sin esperar ejecuciones en curso", sig)
+				return
+			}
+		}
+	}
+}
+
 // Start inicia el scheduler y espera señales
 func (s *Scheduler) Start() error {
+	defer func() {
+		if s.closeAPI != nil {
+			s.closeAPI()
+		}
+	}()
+
 	logger.DebugLog("Iniciando scheduler con pingkey: %s", s.pingKey)
+	// Al iniciar no hay programación que conservar: los problemas se avisan y
+	// los jobs válidos se programan igual
+	if report, err := validate.Check(); err == nil {
+		for _, issue := range report.Issues {
+			logger.DebugLog("Configuración: %s", issue)
+			fmt.Fprintf(os.Stderr, "Advertencia: %s\n", issue)
+		}
+	}
 	// Cargar jobs iniciales
-	if err := s.LoadJobs(); err != nil {
+	if _, err := s.LoadJobs(); err != nil {
 		logger.DebugLog("Error cargando jobs iniciales: %v", err)
 		return err
 	}
@@ -181,7 +297,7 @@ func (s *Scheduler) Start() error {
 				// Recargar configuración
 				logger.DebugLog("Recibida señal SIGHUP, recargando configuración")
 				fmt.Fprintf(os.Stdout, "Recibida señal SIGHUP, recargando configuración...\n")
-				if err := s.Reload(); err != nil {
+				if _, err := s.Reload(); err != nil {
 					logger.DebugLog("Error recargando configuración: %v", err)
 					fmt.Fprintf(os.Stderr, "Error recargando configuración: %v\n", err)
 				} else {
@@ -192,6 +308,7 @@ func (s *Scheduler) Start() error {
 				logger.DebugLog("Recibida señal %v, deteniendo scheduler", sig)
 				fmt.Fprintf(os.Stdout, "Recibida señal %v, deteniendo scheduler...\n", sig)
 				s.Stop()
+				s.waitForRuns(sigChan)
 				logger.DebugLog("Scheduler detenido")
 				return nil
 			}
@@ -199,7 +316,7 @@ func (s *Scheduler) Start() error {
 			return nil
 		case <-s.reloadChan:
 			logger.DebugLog("Recarga manual solicitada")
-			if err := s.Reload(); err != nil {
+			if _, err := s.Reload(); err != nil {
 				logger.DebugLog("Error recargando configuración: %v", err)
 				fmt.Fprintf(os.Stderr, "Error recargando configuración: %v\n", err)
 			} else {
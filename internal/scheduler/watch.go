@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/logger"
+)
+
+// WatchDebounce es el tiempo sin cambios que se espera antes de recargar,
+// para agrupar las ráfagas de escrituras de editores y del propio CLI
+const WatchDebounce = 500 * time.Millisecond
+
+// Watch observa el directorio de configuración y recarga los jobs cuando
+// cambian el archivo de jobs (jobs.json, jobs.yaml...), los de jobs.d o
+// config.json. Se observan los directorios y no los archivos porque muchos
+// editores guardan escribiendo un temporal y renombrándolo.
+// El watcher se detiene junto con el scheduler.
+func (s *Scheduler) Watch() error {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return err
+	}
+	if err := config.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creando watcher: %w", err)
+	}
+	if err := watcher.Add(configDir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("error observando %s: %w", configDir, err)
+	}
+	logger.DebugLog("Observando cambios en %s", configDir)
+	watchJobsDir(watcher)
+
+	go s.watchLoop(watcher, configDir)
+	return nil
+}
+
+// watchJobsDir agrega jobs.d al watcher si existe. Se llama también cuando el
+// directorio se crea con el daemon corriendo.
+func watchJobsDir(watcher *fsnotify.Watcher) {
+	jobsDir, err := config.GetJobsDirPath()
+	if err != nil {
+		return
+	}
+	if info, err := os.Stat(jobsDir); err != nil || !info.IsDir() {
+		return
+	}
+	if err := watcher.Add(jobsDir); err != nil {
+		logger.DebugLog("Error observando %s: %v", jobsDir, err)
+		fmt.Fprintf(os.Stderr, "Error observando %s: %v\n", jobsDir, err)
+		return
+	}
+	logger.DebugLog("Observando cambios en %s", jobsDir)
+}
+
+// watchLoop procesa los eventos del watcher hasta que el scheduler se detiene
+func (s *Scheduler) watchLoop(watcher *fsnotify.Watcher, configDir string) {
+	defer watcher.Close()
+
+	// El timer arranca detenido; cada evento relevante lo reinicia
+	timer := time.NewTimer(WatchDebounce)
+	timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if event.Name == filepath.Join(configDir, config.JobsDir) {
+				// jobs.d se creó (o se eliminó): observarlo y recargar sus archivos
+				if event.Op&fsnotify.Create != 0 {
+					watchJobsDir(watcher)
+				}
+			} else if !isWatchedFile(event.Name, configDir) {
+				continue
+			}
+			logger.DebugLog("Cambio detectado en %s (%s)", event.Name, event.Op)
+			timer.Reset(WatchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.DebugLog("Error del watcher: %v", err)
+			fmt.Fprintf(os.Stderr, "Error observando la configuración: %v\n", err)
+		case <-timer.C:
+			s.reloadFromDisk()
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// reloadFromDisk aplica la configuración en disco. Reload la valida antes:
+// un archivo inválido se rechaza completo y se mantiene la programación actual.
+func (s *Scheduler) reloadFromDisk() {
+	fmt.Fprintf(os.Stdout, "Cambio detectado en la configuración, recargando...\n")
+	if _, err := s.Reload(); err != nil {
+		logger.DebugLog("Error recargando configuración: %v", err)
+		fmt.Fprintf(os.Stderr, "Error recargando configuración: %v\n", err)
+	}
+}
+
+// isWatchedFile indica si un cambio en path debe provocar una recarga
+func isWatchedFile(path, configDir string) bool {
+	name := filepath.Base(path)
+	if filepath.Dir(path) == filepath.Join(configDir, config.JobsDir) {
+		return config.IsJobsDropIn(name)
+	}
+	if name == config.ConfigFile {
+		return true
+	}
+	for _, jobsFile := range config.JobsFileNames {
+		if name == jobsFile {
+			return true
+		}
+	}
+	return false
+}
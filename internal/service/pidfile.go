@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+)
+
+// PIDFile es el archivo (en el directorio de ejecución) con el PID del daemon
+const PIDFile = "orgmcron.pid"
+
+// ErrDaemonNotRunning indica que no hay un daemon al que enviar señales
+var ErrDaemonNotRunning = errors.New("el daemon no está corriendo")
+
+// GetPIDFilePath retorna la ruta del archivo PID del daemon
+func GetPIDFilePath() (string, error) {
+	runtimeDir, err := config.GetRuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(runtimeDir, PIDFile), nil
+}
+
+// daemonLockFile es el archivo (en el directorio de configuración) que el
+// daemon mantiene con flock mientras corre. Está junto a la configuración y
+// no en el directorio de ejecución para que lo vean también procesos con
+// otro XDG_RUNTIME_DIR (ej. el servicio systemd y una terminal).
+const daemonLockFile = ".daemon.lock"
+
+// daemonLock mantiene abierto el archivo del lock: si se cerrara (ej. por el
+// recolector de basura) el lock se liberaría
+var daemonLock *os.File
+
+// ErrDaemonRunning indica que ya hay un daemon corriendo
+var ErrDaemonRunning = errors.New("ya hay un daemon de orgmcron corriendo")
+
+// LockDaemon asegura que solo corra un daemon: toma un flock exclusivo que
+// se mantiene hasta que el proceso termina (el kernel lo libera aunque
+// muera) y comprueba que el archivo PID no apunte a otro daemon vivo. Debe
+// llamarse antes de WritePIDFile.
+func LockDaemon() error {
+	if err := config.EnsureConfigDir(); err != nil {
+		return err
+	}
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filepath.Join(configDir, daemonLockFile), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("error abriendo lock del daemon: %w", err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return ErrDaemonRunning
+		}
+		return fmt.Errorf("error tomando lock del daemon: %w", err)
+	}
+	daemonLock = file
+
+	if pid, ok := DaemonPID(); ok && pid != os.Getpid() {
+		return fmt.Errorf("%w (PID %d)", ErrDaemonRunning, pid)
+	}
+	return nil
+}
+
+// WritePIDFile registra el PID del proceso actual como daemon
+func WritePIDFile() error {
+	if err := config.EnsureRuntimeDir(); err != nil {
+		return err
+	}
+	pidPath, err := GetPIDFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(pidPath, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		return fmt.Errorf("error escribiendo archivo PID: %w", err)
+	}
+	return nil
+}
+
+// RemovePIDFile elimina el archivo PID si pertenece al proceso actual
+func RemovePIDFile() {
+	if pid, ok := readPIDFile(); ok && pid == os.Getpid() {
+		if pidPath, err := GetPIDFilePath(); err == nil {
+			os.Remove(pidPath)
+		}
+	}
+}
+
+// DaemonPID retorna el PID del daemon si el archivo PID apunta a un proceso
+// orgmcron vivo
+func DaemonPID() (int, bool) {
+	pid, ok := readPIDFile()
+	if !ok {
+		return 0, false
+	}
+	if err := syscall.Kill(pid, 0); err != nil && !errors.Is(err, syscall.EPERM) {
+		return 0, false
+	}
+	// Descartar PIDs reutilizados por otros procesos
+	if cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid)); err == nil && !strings.Contains(string(cmdline), "orgmcron") {
+		return 0, false
+	}
+	return pid, true
+}
+
+// IsDaemonRunning indica si hay un daemon corriendo (en primer plano o como servicio)
+func IsDaemonRunning() bool {
+	if _, ok := DaemonPID(); ok {
+		return true
+	}
+	return IsServiceRunning()
+}
+
+// SignalDaemon envía una señal al daemon usando el archivo PID o, si no
+// existe, systemctl (solo al proceso principal, no a los jobs en curso)
+func SignalDaemon(sig syscall.Signal) error {
+	if pid, ok := DaemonPID(); ok {
+		if err := syscall.Kill(pid, sig); err != nil {
+			return fmt.Errorf("error enviando señal al daemon (PID %d): %w", pid, err)
+		}
+		return nil
+	}
+	if IsServiceRunning() {
+		return SignalService(sig)
+	}
+	return ErrDaemonNotRunning
+}
+
+func readPIDFile() (int, bool) {
+	pidPath, err := GetPIDFilePath()
+	if err != nil {
+		return 0, false
+	}
+	data, err := os.ReadFile(pidPath)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, true
+}
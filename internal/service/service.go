@@ -5,6 +5,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"syscall"
+
+	"golang.org/x/sys/unix"
 )
 
 const (
@@ -61,6 +64,7 @@ After=network.target
 [Service]
 Type=simple
 ExecStart=%s start
+ExecReload=/bin/kill -HUP $MAINPID
 Restart=always
 RestartSec=10
 
@@ -146,3 +150,16 @@ func IsServiceRunning() bool {
 	return cmd.Run() == nil
 }
 
+
+// SignalService envía una señal solo al proceso principal del servicio, sin
+// afectar a los jobs en curso
+func SignalService(sig syscall.Signal) error {
+	name := unix.SignalName(sig)
+	cmd := exec.Command("systemctl", "--user", "kill", "--kill-who=main", "-s", name, ServiceName)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error enviando señal %s al servicio: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package validate
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema es el JSON Schema de los archivos de jobs, para los editores
+// (autocompletado y validación) y para Check
+//
+//go:embed jobs.schema.json
+var Schema []byte
+
+// schemaNode es la parte de JSON Schema que usa jobs.schema.json. Las demás
+// palabras clave (description, format...) solo sirven a los editores.
+type schemaNode struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]*schemaNode `json:"properties"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Required             []string               `json:"required"`
+	Items                *schemaNode            `json:"items"`
+	MinItems             *int                   `json:"minItems"`
+	Enum                 []string               `json:"enum"`
+	Pattern              string                 `json:"pattern"`
+	PatternErrorMessage  string                 `json:"patternErrorMessage"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Minimum              *float64               `json:"minimum"`
+	AnyOf                []*schemaNode          `json:"anyOf"`
+	Definitions          map[string]*schemaNode `json:"definitions"`
+
+	pattern    *regexp.Regexp
+	additional *schemaNode
+	closed     bool
+}
+
+// schemaRoot es el schema embebido ya interpretado
+var schemaRoot = mustParseSchema(Schema)
+
+func mustParseSchema(data []byte) *schemaNode {
+	var root schemaNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		panic(fmt.Sprintf("jobs.schema.json inválido: %v", err))
+	}
+	root.compile()
+	for _, def := range root.Definitions {
+		def.compile()
+	}
+	return &root
+}
+
+// compile prepara las expresiones regulares y additionalProperties
+func (n *schemaNode) compile() {
+	if n.Pattern != "" {
+		n.pattern = regexp.MustCompile(n.Pattern)
+	}
+	switch raw := strings.TrimSpace(string(n.AdditionalProperties)); raw {
+	case "":
+	case "false":
+		n.closed = true
+	case "true":
+	default:
+		n.additional = &schemaNode{}
+		if err := json.Unmarshal(n.AdditionalProperties, n.additional); err != nil {
+			panic(fmt.Sprintf("jobs.schema.json inválido: %v", err))
+		}
+	}
+	for _, child := range n.children() {
+		child.compile()
+	}
+}
+
+func (n *schemaNode) children() []*schemaNode {
+	var children []*schemaNode
+	for _, p := range n.Properties {
+		children = append(children, p)
+	}
+	if n.additional != nil {
+		children = append(children, n.additional)
+	}
+	if n.Items != nil {
+		children = append(children, n.Items)
+	}
+	return append(children, n.AnyOf...)
+}
+
+// definition retorna una definición del schema por su nombre
+func definition(name string) *schemaNode {
+	return schemaRoot.Definitions[name]
+}
+
+// resolve sigue las referencias "#/definitions/..."
+func (n *schemaNode) resolve() *schemaNode {
+	for n.Ref != "" {
+		n = definition(strings.TrimPrefix(n.Ref, "#/definitions/"))
+	}
+	return n
+}
+
+// validateValue comprueba v (decodificado de JSON con UseNumber) contra el
+// schema y reporta cada problema con su ruta (ej. jobs[1].retry)
+func (n *schemaNode) validateValue(v interface{}, path string, report func(path, msg string)) {
+	n = n.resolve()
+
+	if len(n.AnyOf) > 0 {
+		// Se valida contra la alternativa de su mismo tipo, así los mensajes
+		// son los de esa alternativa y no un "no coincide con ninguna"
+		var matching []*schemaNode
+		var types []string
+		for _, alt := range n.AnyOf {
+			alt = alt.resolve()
+			types = append(types, typeName(alt.Type))
+			if hasType(v, alt.Type) {
+				matching = append(matching, alt)
+			}
+		}
+		if len(matching) == 0 {
+			report(path, "debe ser "+joinOr(types))
+			return
+		}
+		matching[0].validateValue(v, path, report)
+		return
+	}
+
+	if n.Type != "" && !hasType(v, n.Type) {
+		report(path, "debe ser "+typeName(n.Type))
+		return
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for _, name := range n.Required {
+			if _, ok := value[name]; !ok {
+				report(path, fmt.Sprintf("falta el campo requerido \"%s\"", name))
+			}
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := joinPath(path, key)
+			if prop, ok := n.Properties[key]; ok {
+				prop.validateValue(value[key], child, report)
+			} else if n.additional != nil {
+				n.additional.validateValue(value[key], child, report)
+			} else if n.closed {
+				msg := "campo desconocido"
+				if suggestion := closestKey(key, n.Properties); suggestion != "" {
+					msg += fmt.Sprintf(" (¿quisiste decir \"%s\"?)", suggestion)
+				}
+				report(child, msg)
+			}
+		}
+	case []interface{}:
+		if n.MinItems != nil && len(value) < *n.MinItems {
+			report(path, fmt.Sprintf("debe tener al menos %d elemento(s)", *n.MinItems))
+		}
+		if n.Items != nil {
+			for i, item := range value {
+				n.Items.validateValue(item, fmt.Sprintf("%s[%d]", path, i), report)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(value)
+		if n.MinLength != nil && length < *n.MinLength {
+			report(path, "no puede estar vacío")
+			return
+		}
+		if n.MaxLength != nil && length > *n.MaxLength {
+			report(path, fmt.Sprintf("no puede tener más de %d caracteres", *n.MaxLength))
+			return
+		}
+		if len(n.Enum) > 0 && !contains(n.Enum, value) {
+			report(path, fmt.Sprintf("valor inválido \"%s\" (usa %s)", value, joinOr(n.Enum)))
+			return
+		}
+		if n.pattern != nil && !n.pattern.MatchString(value) {
+			msg := n.PatternErrorMessage
+			if msg == "" {
+				msg = "no coincide con el patrón " + n.Pattern
+			}
+			report(path, fmt.Sprintf("%s: \"%s\"", msg, value))
+		}
+	case json.Number:
+		if f, err := value.Float64(); err == nil && n.Minimum != nil && f < *n.Minimum {
+			report(path, fmt.Sprintf("debe ser mayor o igual a %v", *n.Minimum))
+		}
+	}
+}
+
+// hasType indica si v es del tipo JSON Schema indicado (vacío = cualquiera)
+func hasType(v interface{}, schemaType string) bool {
+	switch schemaType {
+	case "":
+		return true
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	}
+	return false
+}
+
+func typeName(schemaType string) string {
+	switch schemaType {
+	case "object":
+		return "un objeto"
+	case "array":
+		return "una lista"
+	case "string":
+		return "un texto"
+	case "boolean":
+		return "true o false"
+	case "number":
+		return "un número"
+	case "integer":
+		return "un número entero"
+	}
+	return schemaType
+}
+
+// closestKey sugiere la propiedad más parecida a un campo desconocido (ej. un
+// error de tipeo); vacío si ninguna está cerca
+func closestKey(key string, properties map[string]*schemaNode) string {
+	best, bestDistance := "", 3
+	for name := range properties {
+		if d := levenshtein(strings.ToLower(key), name); d < bestDistance || d == bestDistance && best != "" && name < best {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func joinOr(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " o " + items[len(items)-1]
+}
+
+func contains(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,587 @@
+// Package validate comprueba los archivos de configuración de orgmcron antes
+// de aplicarlos: sintaxis, campos según jobs.schema.json y las reglas que el
+// schema no puede expresar (nombres duplicados, schedules, zonas horarias).
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"github.com/osmargm1202/orgmcron/internal/config"
+	"github.com/osmargm1202/orgmcron/internal/logger"
+	"github.com/osmargm1202/orgmcron/internal/schedule"
+	"gopkg.in/yaml.v3"
+)
+
+// Issue es un problema encontrado en un archivo
+type Issue struct {
+	// File es la ruta mostrada (relativa al directorio de configuración)
+	File string
+	// Line y Column ubican el problema en el archivo (0 si no se conocen)
+	Line   int
+	Column int
+	// Path es la ubicación dentro del documento (ej. jobs[1].schedule)
+	Path    string
+	Message string
+	// Warning indica un problema que no impide aplicar la configuración
+	Warning bool
+}
+
+func (i Issue) String() string {
+	if i.File == "" {
+		if i.Path != "" {
+			return fmt.Sprintf("%s: %s", i.Path, i.Message)
+		}
+		return i.Message
+	}
+	location := i.File
+	if i.Line > 0 {
+		location += fmt.Sprintf(":%d", i.Line)
+	}
+	if i.Column > 0 {
+		location += fmt.Sprintf(":%d", i.Column)
+	}
+	if i.Path != "" {
+		return fmt.Sprintf("%s: %s: %s", location, i.Path, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", location, i.Message)
+}
+
+// Report es el resultado de validar la configuración
+type Report struct {
+	Issues []Issue
+	Files  int
+	Jobs   int
+}
+
+// Errors retorna los problemas que impiden aplicar la configuración
+func (r *Report) Errors() []Issue {
+	var errs []Issue
+	for _, issue := range r.Issues {
+		if !issue.Warning {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+// Err resume los errores en uno solo; nil si la configuración es válida
+func (r *Report) Err() error {
+	errs := r.Errors()
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errors.New(errs[0].String())
+	}
+	more := fmt.Sprintf("%d errores más", len(errs)-1)
+	if len(errs) == 2 {
+		more = "1 error más"
+	}
+	return fmt.Errorf("%s (y %s, ver 'orgmcron validate')", errs[0], more)
+}
+
+// Job valida un job antes de guardarlo con las mismas reglas que Check (el
+// schema y las del daemon), usando la zona horaria y la pingkey de
+// config.json. Los avisos no impiden guardarlo. Quien guarda un job (add,
+// edit, pause...) debe llamarla antes de config.AddJob o dentro de
+// config.ModifyJob.
+func Job(j config.Job) error {
+	c := newChecker()
+	if appConfig, err := config.LoadConfig(); err == nil {
+		c.appConfig = *appConfig
+	}
+
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	doc, _, err := decode(data, config.FormatJSON)
+	if err != nil {
+		return err
+	}
+	definition("job").validateValue(doc, "", func(path, msg string) {
+		c.issue(path, msg, false)
+	})
+	c.checkJob(j, "")
+
+	var msgs []string
+	for _, issue := range c.report.Errors() {
+		msgs = append(msgs, issue.String())
+	}
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// Check valida la configuración en disco: config.json, el archivo de jobs y
+// los de jobs.d
+func Check() (*Report, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := config.ConfigFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	c := newChecker()
+	if _, err := config.GetJobsPath(); err != nil {
+		c.add(Issue{Message: err.Error()})
+	}
+	// config.json primero: su zona horaria y pingkey se usan con los jobs
+	for _, rel := range files {
+		if rel == config.ConfigFile {
+			c.checkConfig(filepath.Join(configDir, rel), rel)
+		}
+	}
+	for _, rel := range files {
+		if rel != config.ConfigFile {
+			c.checkJobsFile(filepath.Join(configDir, filepath.FromSlash(rel)), rel)
+		}
+	}
+	c.sortFileIssues()
+	return c.report, nil
+}
+
+// CheckFiles valida archivos de jobs sueltos (ej. antes de copiarlos a
+// jobs.d), con la zona horaria y la pingkey de config.json
+func CheckFiles(paths []string) (*Report, error) {
+	c := newChecker()
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	configPath := filepath.Join(configDir, config.ConfigFile)
+	if _, err := os.Stat(configPath); err == nil {
+		c.checkConfig(configPath, config.ConfigFile)
+		// Solo interesan los problemas de los archivos indicados
+		c.report.Issues, c.report.Files, c.fileStart = nil, 0, 0
+	}
+	for _, path := range paths {
+		c.checkJobsFile(path, path)
+	}
+	c.sortFileIssues()
+	return c.report, nil
+}
+
+// checker acumula los problemas de varios archivos
+type checker struct {
+	report    *Report
+	appConfig config.AppConfig
+	// names guarda dónde se definió cada job, para detectar duplicados
+	names map[string]string
+	// positions ubica las rutas del archivo que se está revisando
+	file      string
+	positions map[string]position
+	// fileStart es el índice del primer problema del archivo actual
+	fileStart int
+}
+
+// sortFileIssues ordena por línea los problemas del archivo revisado
+func (c *checker) sortFileIssues() {
+	issues := c.report.Issues[c.fileStart:]
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+}
+
+type position struct {
+	line, column int
+}
+
+func newChecker() *checker {
+	return &checker{report: &Report{}, names: map[string]string{}}
+}
+
+func (c *checker) add(issue Issue) {
+	c.report.Issues = append(c.report.Issues, issue)
+}
+
+// issue crea un problema del archivo actual, ubicándolo por su ruta (o por
+// la ruta más cercana que tenga posición)
+func (c *checker) issue(path, msg string, warning bool) {
+	issue := Issue{File: c.file, Path: path, Message: msg, Warning: warning}
+	for p := path; ; p = parentPath(p) {
+		if pos, ok := c.positions[p]; ok {
+			issue.Line, issue.Column = pos.line, pos.column
+			break
+		}
+		if p == "" {
+			break
+		}
+	}
+	c.add(issue)
+}
+
+// read lee y decodifica un archivo; si no se puede reporta el problema y
+// retorna nil
+func (c *checker) read(path, name string) interface{} {
+	c.sortFileIssues()
+	c.file = name
+	c.positions = nil
+	c.fileStart = len(c.report.Issues)
+	c.report.Files++
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.add(Issue{File: name, Message: err.Error()})
+		return nil
+	}
+	format, err := config.FormatFromPath(path)
+	if err != nil {
+		c.add(Issue{File: name, Message: err.Error()})
+		return nil
+	}
+
+	doc, positions, err := decode(data, format)
+	if err != nil {
+		issue := Issue{File: name, Message: "error de sintaxis: " + err.Error()}
+		issue.Line, issue.Column = syntaxPosition(data, err)
+		c.add(issue)
+		return nil
+	}
+	c.positions = positions
+	return doc
+}
+
+// checkConfig valida config.json
+func (c *checker) checkConfig(path, name string) {
+	doc := c.read(path, name)
+	if doc == nil {
+		return
+	}
+	before := len(c.report.Errors())
+	definition("config").validateValue(doc, "", func(path, msg string) {
+		c.issue(path, msg, false)
+	})
+	if len(c.report.Errors()) > before {
+		return
+	}
+
+	data, _ := json.Marshal(doc)
+	if err := json.Unmarshal(data, &c.appConfig); err != nil {
+		c.issue("", err.Error(), false)
+		return
+	}
+	if _, err := schedule.LoadLocation(c.appConfig.Timezone); err != nil {
+		c.issue("timezone", err.Error(), false)
+		// Se reporta una vez aquí y no en cada job
+		c.appConfig.Timezone = ""
+	}
+}
+
+// checkJobsFile valida un archivo de jobs: primero contra el schema y luego
+// cada job con las reglas del daemon
+func (c *checker) checkJobsFile(path, name string) {
+	doc := c.read(path, name)
+	if doc == nil {
+		return
+	}
+
+	// Misma regla que al cargar: sin lista "jobs" el archivo es un solo job
+	var def string
+	var items []interface{}
+	switch value := doc.(type) {
+	case nil:
+		return
+	case map[string]interface{}:
+		if list, ok := value["jobs"]; ok {
+			def = "jobsFile"
+			items, _ = list.([]interface{})
+		} else if len(value) > 1 || len(value) == 1 && value[config.SchemaKey] == nil {
+			def = "job"
+			items = []interface{}{doc}
+		} else {
+			return
+		}
+	default:
+		c.issue("", "debe ser un objeto con la lista \"jobs\" o un job", false)
+		return
+	}
+
+	// Las reglas del daemon se revisan también en los jobs con errores de
+	// estructura, para reportar todo de una vez
+	invalid := map[string]bool{}
+	definition(def).validateValue(doc, "", func(path, msg string) {
+		c.issue(path, msg, false)
+		invalid[jobPath(path, def)] = true
+	})
+
+	for i, item := range items {
+		path := ""
+		if def == "jobsFile" {
+			path = fmt.Sprintf("jobs[%d]", i)
+		}
+		if _, ok := item.(map[string]interface{}); !ok {
+			continue
+		}
+		// json.Unmarshal completa los demás campos aunque uno tenga un tipo
+		// incorrecto, que el schema ya reportó
+		data, _ := json.Marshal(item)
+		var j config.Job
+		if err := json.Unmarshal(data, &j); err != nil && !invalid[path] {
+			c.issue(path, err.Error(), false)
+			continue
+		}
+		c.checkJob(j, path)
+	}
+	c.report.Jobs += len(items)
+}
+
+// jobPath retorna la ruta del job al que pertenece path (ej. jobs[1].retry ->
+// jobs[1]); en un archivo de un solo job es la raíz
+func jobPath(path, def string) string {
+	if def != "jobsFile" || !strings.HasPrefix(path, "jobs[") {
+		return ""
+	}
+	if i := strings.Index(path, "]"); i >= 0 {
+		return path[:i+1]
+	}
+	return path
+}
+
+// checkJob aplica a un job las reglas que el schema no expresa
+func (c *checker) checkJob(j config.Job, path string) {
+	field := func(name string) string {
+		return joinPath(path, name)
+	}
+
+	// Un nombre o un schedule vacío ya lo reporta el schema
+	if j.Name != "" {
+		if j.Name == strings.TrimSuffix(logger.DebugLogFile, ".log") {
+			c.issue(field("name"), fmt.Sprintf("'%s' es un nombre reservado: su log se mezclaría con %s", j.Name, logger.DebugLogFile), false)
+		}
+		if first, ok := c.names[j.Name]; !ok {
+			c.names[j.Name] = c.file
+		} else if first == c.file {
+			c.issue(field("name"), fmt.Sprintf("job '%s' duplicado en este archivo", j.Name), false)
+		} else {
+			c.issue(field("name"), fmt.Sprintf("job '%s' duplicado: ya está definido en %s", j.Name, first), false)
+		}
+	}
+
+	timezone := c.appConfig.Timezone
+	if j.Timezone != "" {
+		if _, err := schedule.LoadLocation(j.Timezone); err != nil {
+			c.issue(field("timezone"), err.Error(), false)
+		} else {
+			timezone = j.Timezone
+		}
+	}
+	if j.Schedule != "" {
+		if _, err := schedule.ParseIn(j.Schedule, timezone); err != nil {
+			c.issue(field("schedule"), err.Error(), false)
+		}
+	}
+
+	if _, err := j.PausedUntilTime(); err != nil {
+		c.issue(field("paused_until"), err.Error(), false)
+	}
+
+	// La forma de la URL la revisa el schema
+	if strings.Contains(j.HealthcheckURL, "{pingkey}") && c.appConfig.PingKey == "" {
+		c.issue(field("healthcheck_url"), "usa {pingkey} pero no hay pingkey configurada (orgmcron config pingkey)", true)
+	}
+}
+
+// decode interpreta un archivo y retorna el documento (como lo daría JSON,
+// con números json.Number) y la posición de cada ruta cuando el formato la
+// conserva (JSON y YAML)
+func decode(data []byte, format string) (interface{}, map[string]position, error) {
+	positions := map[string]position{}
+	switch format {
+	case config.FormatJSON:
+		if err := jsonPositions(data, positions); err != nil {
+			return nil, nil, err
+		}
+	case config.FormatYAML:
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, nil, err
+		}
+		yamlPositions(&node, "", positions)
+	}
+
+	converted, err := config.ToJSON(data, format)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(bytes.TrimSpace(converted)) == 0 {
+		return nil, positions, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(converted))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+	if decoder.More() {
+		return nil, nil, fmt.Errorf("contenido extra después del documento")
+	}
+	return doc, positions, nil
+}
+
+// jsonPositions recorre un JSON con el tokenizer y guarda dónde empieza cada
+// clave y cada elemento de lista. También detecta los errores de sintaxis.
+func jsonPositions(data []byte, positions map[string]position) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var walk func(path string) error
+	walk = func(path string) error {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'):
+			for decoder.More() {
+				offset := skipSeparators(data, int(decoder.InputOffset()))
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				child := joinPath(path, key.(string))
+				positions[child] = offsetPosition(data, offset)
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+		case json.Delim('['):
+			for i := 0; decoder.More(); i++ {
+				offset := skipSeparators(data, int(decoder.InputOffset()))
+				child := fmt.Sprintf("%s[%d]", path, i)
+				positions[child] = offsetPosition(data, offset)
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+		}
+		return nil
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := walk(""); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return &extraDataError{offset: int64(skipSeparators(data, int(decoder.InputOffset())))}
+	}
+	return nil
+}
+
+// extraDataError es contenido después del documento JSON (ej. un "}" de más)
+type extraDataError struct {
+	offset int64
+}
+
+func (e *extraDataError) Error() string {
+	return "contenido extra después del documento"
+}
+
+// yamlPositions guarda la posición de cada clave y elemento de un documento YAML
+func yamlPositions(node *yaml.Node, path string, positions map[string]position) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			yamlPositions(child, path, positions)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			child := joinPath(path, key.Value)
+			positions[child] = position{key.Line, key.Column}
+			yamlPositions(node.Content[i+1], child, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			positions[child] = position{item.Line, item.Column}
+			yamlPositions(item, child, positions)
+		}
+	}
+}
+
+// yamlErrorLine extrae la línea de un error de yaml.v3 ("yaml: line 3: ...")
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+):`)
+
+// syntaxPosition ubica un error de sintaxis; de YAML solo se conoce la línea
+func syntaxPosition(data []byte, err error) (int, int) {
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return line, 0
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var extraErr *extraDataError
+	var tomlErr toml.ParseError
+	switch {
+	case errors.As(err, &syntaxErr):
+		// Offset cuenta los bytes leídos, incluido el carácter inválido
+		pos := offsetPosition(data, max(int(syntaxErr.Offset)-1, 0))
+		return pos.line, pos.column
+	case errors.As(err, &typeErr):
+		pos := offsetPosition(data, int(typeErr.Offset))
+		return pos.line, pos.column
+	case errors.As(err, &extraErr):
+		pos := offsetPosition(data, int(extraErr.offset))
+		return pos.line, pos.column
+	case errors.As(err, &tomlErr):
+		return tomlErr.Position.Line, tomlErr.Position.Col
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		pos := offsetPosition(data, len(data))
+		return pos.line, pos.column
+	}
+	return 0, 0
+}
+
+// offsetPosition convierte un offset en bytes a línea y columna (desde 1)
+func offsetPosition(data []byte, offset int) position {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:]) + 1
+	return position{line, column}
+}
+
+// skipSeparators avanza desde offset hasta el inicio del siguiente token
+func skipSeparators(data []byte, offset int) int {
+	for offset < len(data) && strings.ContainsRune(" \t\r\n,:", rune(data[offset])) {
+		offset++
+	}
+	return offset
+}
+
+// parentPath retorna la ruta que contiene a path (jobs[1].retry -> jobs[1] -> jobs)
+func parentPath(path string) string {
+	if i := strings.LastIndexAny(path, ".["); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
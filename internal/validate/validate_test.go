@@ -0,0 +1,229 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/osmargm1202/orgmcron/internal/config"
+)
+
+// checkFile valida contenido como un archivo de jobs suelto, con un HOME
+// vacío para no leer la configuración del usuario
+func checkFile(t *testing.T, name, content string) *Report {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := CheckFiles([]string{path})
+	if err != nil {
+		t.Fatalf("CheckFiles: %v", err)
+	}
+	return report
+}
+
+// wantIssue es un problema esperado; Line y Column 0 no se comprueban
+type wantIssue struct {
+	path    string
+	line    int
+	column  int
+	message string
+	warning bool
+}
+
+func assertIssues(t *testing.T, report *Report, want []wantIssue) {
+	t.Helper()
+	if len(report.Issues) != len(want) {
+		t.Fatalf("%d problemas, want %d: %v", len(report.Issues), len(want), report.Issues)
+	}
+	for i, w := range want {
+		got := report.Issues[i]
+		if got.Path != w.path || !strings.Contains(got.Message, w.message) || got.Warning != w.warning {
+			t.Errorf("problema %d = %+v, want %+v", i, got, w)
+		}
+		if w.line > 0 && (got.Line != w.line || got.Column != w.column) {
+			t.Errorf("problema %d en %d:%d, want %d:%d", i, got.Line, got.Column, w.line, w.column)
+		}
+	}
+}
+
+func TestCheckFilesJobNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobName string
+		valid   bool
+	}{
+		{"simple", "backup", true},
+		{"con espacios y acentos", "copia de año", true},
+		{"ruta relativa", "../../pwn", false},
+		{"barra", "a/b", false},
+		{"barra invertida", `a\b`, false},
+		{"oculto", ".oculto", false},
+		{"guion inicial", "-rf", false},
+		{"espacio inicial", " backup", false},
+		{"control", "a\tb", false},
+		{"reservado", "debug", false},
+		{"muy largo", strings.Repeat("a", 201), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := strings.NewReplacer(`\`, `\\`, "\t", `\t`).Replace(tt.jobName)
+			report := checkFile(t, "job.json", `{"name": "`+name+`", "schedule": "@daily", "commands": ["true"]}`)
+			if valid := report.Err() == nil; valid != tt.valid {
+				t.Errorf("nombre %q: válido = %v, want %v (%v)", tt.jobName, valid, tt.valid, report.Issues)
+			}
+		})
+	}
+}
+
+func TestCheckFilesFields(t *testing.T) {
+	job := func(fields string) string {
+		return `{"name": "x", "schedule": "@daily", "commands": ["true"]` + fields + `}`
+	}
+	tests := []struct {
+		name    string
+		content string
+		path    string
+		message string
+	}{
+		{"on_error", job(`, "on_error": "ignore"`), "on_error", `valor inválido "ignore"`},
+		{"exit_code_policy", job(`, "exit_code_policy": "first"`), "exit_code_policy", `valor inválido "first"`},
+		{"concurrency", job(`, "concurrency": "parallel"`), "concurrency", `valor inválido "parallel"`},
+		{"timeout", job(`, "timeout": "10 minutos"`), "timeout", "duración inválida"},
+		{"timeout sin unidad", job(`, "timeout": "30"`), "timeout", "duración inválida"},
+		{"timeout de comando", `{"name": "x", "schedule": "@daily", "commands": [{"run": "true", "timeout": "1d"}]}`, "commands[0].timeout", "duración inválida"},
+		{"retry", job(`, "retry": {"max_attempts": 3, "initial_delay": "soon"}`), "retry.initial_delay", "duración inválida"},
+		{"retry sin max_attempts", job(`, "retry": {}`), "retry", `falta el campo requerido "max_attempts"`},
+		{"healthcheck", job(`, "healthcheck_url": "hc.or-gm.com/ping/x"`), "healthcheck_url", "URL http(s) completa"},
+		{"campo desconocido", job(`, "timout": "1h"`), "timout", `¿quisiste decir "timeout"?`},
+		{"tipo", job(`, "healthcheck_attach_output": "yes"`), "healthcheck_attach_output", "debe ser true o false"},
+		{"sin comandos", `{"name": "x", "schedule": "@daily", "commands": []}`, "commands", "al menos 1"},
+		{"schedule", `{"name": "x", "schedule": "0 0 30 2 *", "commands": ["true"]}`, "schedule", "nunca se ejecuta"},
+		{"zona horaria", job(`, "timezone": "Mars/Olympus"`), "timezone", "Mars/Olympus"},
+		{"paused_until", job(`, "enabled": false, "paused_until": "mañana"`), "paused_until", "paused_until inválido"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := checkFile(t, "job.json", tt.content)
+			assertIssues(t, report, []wantIssue{{path: tt.path, message: tt.message}})
+		})
+	}
+}
+
+func TestCheckFilesPositions(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []wantIssue
+	}{
+		{
+			name: "json",
+			file: "jobs.json",
+			content: `{
+  "jobs": [
+    {"name": "a", "schedule": "@daily", "commands": ["true"]},
+    {
+      "name": "b",
+      "schedule": "@daily",
+      "commands": ["true"],
+      "on_error": "nope"
+    }
+  ]
+}`,
+			want: []wantIssue{{path: "jobs[1].on_error", line: 8, column: 7, message: "valor inválido"}},
+		},
+		{
+			name: "yaml",
+			file: "jobs.yaml",
+			content: `jobs:
+  - name: a
+    schedule: "@daily"
+    commands: ["true"]
+  - name: a
+    schedule: "@hourly"
+    commands: ["true"]
+    timeout: 5 minutes
+`,
+			want: []wantIssue{
+				{path: "jobs[1].name", line: 5, column: 5, message: "duplicado en este archivo"},
+				{path: "jobs[1].timeout", line: 8, column: 5, message: "duración inválida"},
+			},
+		},
+		{
+			name:    "sintaxis json",
+			file:    "job.json",
+			content: "{\n  \"name\": \"x\",\n  \"schedule\": \"@daily\"\n  \"commands\": []\n}",
+			want:    []wantIssue{{line: 4, column: 3, message: "error de sintaxis"}},
+		},
+		{
+			name:    "sintaxis yaml",
+			file:    "job.yaml",
+			content: "name: x\nschedule: \"@daily\ncommands: []\n",
+			want:    []wantIssue{{line: 2, message: "error de sintaxis"}},
+		},
+		{
+			name:    "sintaxis toml",
+			file:    "job.toml",
+			content: "name = \"x\"\nschedule = @daily\n",
+			want:    []wantIssue{{line: 2, message: "error de sintaxis"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := checkFile(t, tt.file, tt.content)
+			for i := range tt.want {
+				if tt.want[i].line > 0 && tt.want[i].column == 0 {
+					// Sin columna conocida solo se compara la línea
+					if len(report.Issues) > i {
+						tt.want[i].column = report.Issues[i].Column
+					}
+				}
+			}
+			assertIssues(t, report, tt.want)
+		})
+	}
+}
+
+func TestCheckFilesPingkeyWarning(t *testing.T) {
+	report := checkFile(t, "job.json", `{"name": "x", "schedule": "@daily", "commands": ["true"], "healthcheck_url": "https://hc.or-gm.com/ping/{pingkey}/x"}`)
+	assertIssues(t, report, []wantIssue{{path: "healthcheck_url", message: "no hay pingkey", warning: true}})
+	if err := report.Err(); err != nil {
+		t.Errorf("un aviso no debe invalidar la configuración: %v", err)
+	}
+}
+
+func TestJob(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	valid := config.Job{Name: "backup", Schedule: "@daily", Commands: []config.Command{{Run: "true"}}}
+
+	tests := []struct {
+		name    string
+		modify  func(j *config.Job)
+		message string
+	}{
+		{"válido", func(j *config.Job) {}, ""},
+		{"nombre", func(j *config.Job) { j.Name = "../../pwn" }, "name: nombre inválido"},
+		{"on_error", func(j *config.Job) { j.OnError = "bogus" }, "on_error: valor inválido"},
+		{"concurrency", func(j *config.Job) { j.Concurrency = "nope" }, "concurrency: valor inválido"},
+		{"healthcheck", func(j *config.Job) { j.HealthcheckURL = "ftp:/x" }, "healthcheck_url: debe ser una URL"},
+		{"schedule", func(j *config.Job) { j.Schedule = "61 * * * *" }, "schedule: schedule inválido"},
+		{"aviso de pingkey", func(j *config.Job) { j.HealthcheckURL = "https://hc.or-gm.com/ping/{pingkey}/x" }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := valid
+			tt.modify(&j)
+			err := Job(j)
+			switch {
+			case tt.message == "" && err != nil:
+				t.Errorf("Job() = %v, want nil", err)
+			case tt.message != "" && (err == nil || !strings.Contains(err.Error(), tt.message)):
+				t.Errorf("Job() = %v, want %q", err, tt.message)
+			}
+		})
+	}
+}